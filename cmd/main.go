@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -57,16 +58,41 @@ func main() {
 	userRepo := postgres.NewPostgresUserRepository(rootLogger)
 	prRepo := postgres.NewPostgresPullRequestRepository(rootLogger)
 	teamRepo := postgres.NewPostgresTeamRepository(rootLogger)
+	ruleRepo := postgres.NewPostgresReviewerRuleRepository(rootLogger)
+	assignmentRepo := postgres.NewPostgresAssignmentRepository(rootLogger)
+	reviewRepo := postgres.NewPostgresReviewRepository(rootLogger)
+	commentRepo := postgres.NewPostgresCommentRepository(rootLogger)
+	absenceRepo := postgres.NewPostgresAbsenceRepository(rootLogger)
 
 	rootLogger.Info("Setting up services")
-	userService := service.NewUserService(rootLogger, pool, userRepo, prRepo)
-	prService := service.NewPullRequestService(rootLogger, pool, prRepo, userRepo)
+	prService := service.NewPullRequestService(
+		rootLogger,
+		pool,
+		prRepo,
+		userRepo,
+		teamRepo,
+		ruleRepo,
+		assignmentRepo,
+		reviewRepo,
+		commentRepo,
+		rand.NewPCG(rand.Uint64(), rand.Uint64()),
+	)
+	userService := service.NewUserService(
+		rootLogger,
+		pool,
+		userRepo,
+		prRepo,
+		absenceRepo,
+		prService,
+	)
 	teamService := service.NewTeamService(rootLogger, pool, userRepo, teamRepo)
+	ruleService := service.NewReviewerRuleService(rootLogger, pool, ruleRepo, userRepo)
 
 	rootLogger.Info("Setting up handlers")
 	userHandler := handler.NewUserHandler(rootLogger, userService)
 	teamHandler := handler.NewTeamHandler(rootLogger, teamService)
 	prHandler := handler.NewPullRequestHandler(rootLogger, prService)
+	ruleHandler := handler.NewReviewerRuleHandler(rootLogger, ruleService)
 
 	rootLogger.Info("Setting up router")
 	router := chi.NewRouter()
@@ -85,18 +111,52 @@ func main() {
 	router.Route("/team", func(r chi.Router) {
 		r.Post("/add", teamHandler.AddTeam)
 		r.Get("/get", teamHandler.GetTeam)
+		r.Post("/setSettings", teamHandler.SetTeamSettings)
+		r.Post("/setFallbackTeams", teamHandler.SetFallbackTeams)
+		r.Get("/getCodeOwners", teamHandler.GetCodeOwners)
+		r.Post("/setCodeOwners", teamHandler.SetCodeOwners)
+		r.Get("/getRepositories", teamHandler.GetRepositories)
+		r.Post("/setRepository", teamHandler.SetRepository)
 	})
 
 	router.Route("/users", func(r chi.Router) {
 		r.Post("/setIsActive", userHandler.SetIsActive)
+		r.Post("/setSkills", userHandler.SetSkills)
+		r.Post("/setMaxOpenReviews", userHandler.SetMaxOpenReviews)
+		r.Post("/addAbsence", userHandler.AddAbsence)
+		r.Get("/getAbsences", userHandler.GetAbsences)
+		r.Post("/cancelAbsence", userHandler.CancelAbsence)
 		r.Get("/getReview", userHandler.GetReview)
 	})
 
+	router.Route("/reviewerRules", func(r chi.Router) {
+		r.Get("/get", ruleHandler.GetRules)
+		r.Post("/set", ruleHandler.SetRule)
+		r.Post("/remove", ruleHandler.RemoveRule)
+	})
+
 	router.Route("/pullRequest", func(r chi.Router) {
 		r.Get("/openByReviewers", prHandler.GetOpenPullRequestsByReviewers)
+		r.Get("/get", prHandler.GetPullRequest)
+		r.Get("/list", prHandler.ListPullRequests)
 		r.Post("/create", prHandler.CreatePullRequest)
+		r.Post("/previewAssignment", prHandler.PreviewAssignment)
 		r.Post("/merge", prHandler.MergePullRequest)
+		r.Post("/update", prHandler.UpdatePullRequest)
+		r.Post("/review", prHandler.SubmitReview)
+		r.Post("/addComment", prHandler.AddComment)
+		r.Get("/comments", prHandler.GetComments)
+		r.Post("/resolveComment", prHandler.ResolveComment)
+		r.Post("/markReady", prHandler.MarkReadyPullRequest)
+		r.Post("/close", prHandler.ClosePullRequest)
+		r.Post("/reopen", prHandler.ReopenPullRequest)
 		r.Post("/reassign", prHandler.ReassignPullRequest)
+		r.Post("/addReviewer", prHandler.AddReviewer)
+		r.Post("/removeReviewer", prHandler.RemoveReviewer)
+		r.Post("/rebalance", prHandler.RebalanceTeam)
+		r.Get("/history", prHandler.GetPullRequestHistory)
+		r.Get("/assignments", prHandler.GetAssignments)
+		r.Get("/replayAssignment", prHandler.ReplayAssignment)
 	})
 
 	rootLogger.Info("Starting server", "port", appPort)
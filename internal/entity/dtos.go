@@ -1,14 +1,19 @@
 package entity
 
+import "time"
+
 type ErrorDTO struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
 type UserDTO struct {
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserId   string   `json:"user_id"`
+	Username string   `json:"username"`
+	IsActive bool     `json:"is_active"`
+	Skills   []string `json:"skills,omitempty"`
+
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
 }
 
 type UserPullRequestsDTO struct {
@@ -21,42 +26,398 @@ type SetUserIsActiveDTO struct {
 	IsActive bool   `json:"is_active"`
 }
 
+type AffectedPullRequestDTO struct {
+	PullRequestId string   `json:"pull_request_id"`
+	ReplacedBy    []string `json:"replaced_by,omitempty"`
+	Uncovered     bool     `json:"uncovered"`
+}
+
+type SetUserIsActiveResponseDTO struct {
+	UserDTO
+	AffectedPullRequests []AffectedPullRequestDTO `json:"affected_pull_requests,omitempty"`
+}
+
+type AbsenceDTO struct {
+	AbsenceId int64   `json:"absence_id,omitempty"`
+	UserId    string  `json:"user_id"`
+	Kind      string  `json:"kind"`
+	From      *string `json:"from,omitempty"`
+	To        *string `json:"to,omitempty"`
+	Weekday   *int    `json:"weekday,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+type CancelAbsenceDTO struct {
+	UserId    string `json:"user_id"`
+	AbsenceId int64  `json:"absence_id"`
+}
+
+type UserAbsencesDTO struct {
+	UserId   string       `json:"user_id"`
+	Absences []AbsenceDTO `json:"absences"`
+}
+
+type SetUserMaxOpenReviewsDTO struct {
+	UserId         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+type SetUserSkillsDTO struct {
+	UserId string   `json:"user_id"`
+	Skills []string `json:"skills"`
+}
+
 type TeamDTO struct {
-	TeamName string    `json:"team_name"`
-	Members  []UserDTO `json:"members"`
+	TeamName                string    `json:"team_name"`
+	ReviewerStrategy        string    `json:"reviewer_strategy,omitempty"`
+	MinReviewers            *int      `json:"min_reviewers,omitempty"`
+	MaxReviewers            *int      `json:"max_reviewers,omitempty"`
+	MaxOpenReviews          *int      `json:"max_open_reviews,omitempty"`
+	ReviewWindowDays        *int      `json:"review_window_days,omitempty"`
+	RequiredApprovals       *int      `json:"required_approvals,omitempty"`
+	RequireResolvedComments *bool     `json:"require_resolved_comments,omitempty"`
+	FallbackTeams           []string  `json:"fallback_teams,omitempty"`
+	Members                 []UserDTO `json:"members"`
+}
+
+// TeamSettingsDTO changes the settings of an existing team that are set.
+type TeamSettingsDTO struct {
+	TeamName         string  `json:"team_name"`
+	ReviewerStrategy *string `json:"reviewer_strategy,omitempty"`
+	MinReviewers     *int    `json:"min_reviewers,omitempty"`
+	MaxReviewers     *int    `json:"max_reviewers,omitempty"`
+	MaxOpenReviews   *int    `json:"max_open_reviews,omitempty"`
+	// ClearMaxOpenReviews removes the default limit of open reviews.
+	ClearMaxOpenReviews     bool  `json:"clear_max_open_reviews,omitempty"`
+	ReviewWindowDays        *int  `json:"review_window_days,omitempty"`
+	RequiredApprovals       *int  `json:"required_approvals,omitempty"`
+	RequireResolvedComments *bool `json:"require_resolved_comments,omitempty"`
+}
+
+type SetFallbackTeamsDTO struct {
+	TeamName      string   `json:"team_name"`
+	FallbackTeams []string `json:"fallback_teams"`
 }
 
 type ResponseTeamDTO struct {
 	Team TeamDTO `json:"team"`
 }
 
+type RepositoryDTO struct {
+	RepositoryName      string `json:"repository_name"`
+	TeamName            string `json:"team_name"`
+	RouteReviewsToOwner bool   `json:"route_reviews_to_owner"`
+}
+
+type TeamRepositoriesDTO struct {
+	TeamName     string          `json:"team_name"`
+	Repositories []RepositoryDTO `json:"repositories"`
+}
+
+type CodeOwnerRuleDTO struct {
+	Pattern string   `json:"pattern"`
+	Users   []string `json:"users,omitempty"`
+	Teams   []string `json:"teams,omitempty"`
+}
+
+type TeamCodeOwnersDTO struct {
+	TeamName string             `json:"team_name"`
+	Rules    []CodeOwnerRuleDTO `json:"rules"`
+}
+
+type ReviewerRuleDTO struct {
+	AuthorId   string `json:"author_id"`
+	ReviewerId string `json:"reviewer_id"`
+	Kind       string `json:"kind"`
+}
+
+type RemoveReviewerRuleDTO struct {
+	AuthorId   string `json:"author_id"`
+	ReviewerId string `json:"reviewer_id"`
+}
+
+type AuthorReviewerRulesDTO struct {
+	AuthorId string            `json:"author_id"`
+	Rules    []ReviewerRuleDTO `json:"rules"`
+}
+
+// PullRequestCreateDTO creates a PR of a repository when RepositoryName
+// and Number are set, PullRequestId is then always "<repository>#<number>"
+// and must be left empty. Ids of other PRs can't contain '#'.
 type PullRequestCreateDTO struct {
-	PullRequestId   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorId        string `json:"author_id"`
+	PullRequestId   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	Description     string   `json:"description,omitempty"`
+	AuthorId        string   `json:"author_id"`
+	ChangedPaths    []string `json:"changed_paths,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+	Draft           bool     `json:"draft,omitempty"`
+	ParentId        *string  `json:"parent_id,omitempty"`
+	RepositoryName  *string  `json:"repository_name,omitempty"`
+	Number          *int     `json:"number,omitempty"`
+}
+
+type SubmitReviewDTO struct {
+	PullRequestId string `json:"pull_request_id"`
+	UserId        string `json:"user_id"`
+	Decision      string `json:"decision"`
+	Comment       string `json:"comment,omitempty"`
+}
+
+type ReviewDTO struct {
+	UserId      string `json:"user_id"`
+	Decision    string `json:"decision"`
+	Comment     string `json:"comment,omitempty"`
+	SubmittedAt string `json:"submitted_at"`
+}
+
+type AddCommentDTO struct {
+	PullRequestId string `json:"pull_request_id"`
+	UserId        string `json:"user_id"`
+	Body          string `json:"body"`
+	ParentId      *int64 `json:"parent_id,omitempty"`
+}
+
+type ResolveCommentDTO struct {
+	CommentId int64  `json:"comment_id"`
+	UserId    string `json:"user_id"`
+}
+
+type CommentDTO struct {
+	CommentId  int64        `json:"comment_id"`
+	ParentId   *int64       `json:"parent_id,omitempty"`
+	AuthorId   string       `json:"author_id"`
+	Body       string       `json:"body"`
+	Resolved   bool         `json:"resolved"`
+	ResolvedBy *string      `json:"resolved_by,omitempty"`
+	ResolvedAt *string      `json:"resolved_at,omitempty"`
+	CreatedAt  string       `json:"created_at"`
+	Replies    []CommentDTO `json:"replies,omitempty"`
+}
+
+type CommentResponseDTO struct {
+	Comment CommentDTO `json:"comment"`
+}
+
+type PullRequestCommentsDTO struct {
+	PullRequestId string       `json:"pull_request_id"`
+	Threads       []CommentDTO `json:"threads"`
+}
+
+// UpdatePullRequestDTO changes the fields that are set, Labels replaces
+// all labels of the PR and an empty ParentId unstacks it.
+type UpdatePullRequestDTO struct {
+	PullRequestId   string    `json:"pull_request_id"`
+	PullRequestName *string   `json:"pull_request_name,omitempty"`
+	Description     *string   `json:"description,omitempty"`
+	Labels          *[]string `json:"labels,omitempty"`
+	ParentId        *string   `json:"parent_id,omitempty"`
+	ActorId         string    `json:"actor_id,omitempty"`
+}
+
+type MarkReadyPullRequestDTO struct {
+	PullRequestId string `json:"pull_request_id"`
+	ActorId       string `json:"actor_id,omitempty"`
 }
 
 type MergePullRequestDTO struct {
 	PullRequestId string `json:"pull_request_id"`
+	ActorId       string `json:"actor_id,omitempty"`
+}
+
+type ClosePullRequestDTO struct {
+	PullRequestId string `json:"pull_request_id"`
+	ActorId       string `json:"actor_id,omitempty"`
+}
+
+type ReopenPullRequestDTO struct {
+	PullRequestId string `json:"pull_request_id"`
+	ActorId       string `json:"actor_id,omitempty"`
 }
 
 type ReassignPullRequestDTO struct {
+	PullRequestId string  `json:"pull_request_id"`
+	OldReviewerId string  `json:"old_reviewer_id"`
+	NewReviewerId *string `json:"new_reviewer_id,omitempty"`
+	ActorId       string  `json:"actor_id,omitempty"`
+}
+
+type PullRequestReviewerDTO struct {
+	PullRequestId string `json:"pull_request_id"`
+	UserId        string `json:"user_id"`
+	ActorId       string `json:"actor_id,omitempty"`
+}
+
+type PullRequestEventDTO struct {
+	EventId    int64    `json:"event_id"`
+	Kind       string   `json:"kind"`
+	ActorId    *string  `json:"actor_id,omitempty"`
+	UserId     *string  `json:"user_id,omitempty"`
+	FromStatus *string  `json:"from_status,omitempty"`
+	ToStatus   *string  `json:"to_status,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type PullRequestHistoryDTO struct {
+	PullRequestId string                `json:"pull_request_id"`
+	Events        []PullRequestEventDTO `json:"events"`
+}
+
+// PullRequestDependencyDTO is an ancestor of a stacked PR, PR responses
+// list them from the parent up.
+type PullRequestDependencyDTO struct {
 	PullRequestId string `json:"pull_request_id"`
-	OldReviewerId string `json:"old_reviewer_id"`
+	Status        string `json:"status"`
 }
 
 type PullRequestDTO struct {
-	PullRequestId     string   `json:"pull_request_id"`
-	PullRequestName   string   `json:"pull_request_name"`
-	AuthorId          string   `json:"author_id"`
-	Status            string   `json:"status"`
-	AssignedReviewers []string `json:"assigned_reviewers,omitempty"`
-	MergedAt          *string  `json:"mergedAt,omitempty"`
+	PullRequestId     string                     `json:"pull_request_id"`
+	PullRequestName   string                     `json:"pull_request_name"`
+	Description       string                     `json:"description,omitempty"`
+	AuthorId          string                     `json:"author_id"`
+	Status            string                     `json:"status"`
+	AssignedReviewers []string                   `json:"assigned_reviewers,omitempty"`
+	Labels            []string                   `json:"labels,omitempty"`
+	Reviews           []ReviewDTO                `json:"reviews,omitempty"`
+	CreatedAt         *string                    `json:"createdAt,omitempty"`
+	MergedAt          *string                    `json:"mergedAt,omitempty"`
+	ClosedAt          *string                    `json:"closedAt,omitempty"`
+	UnresolvedThreads int                        `json:"unresolved_threads,omitempty"`
+	ParentId          *string                    `json:"parent_id,omitempty"`
+	Dependencies      []PullRequestDependencyDTO `json:"dependencies,omitempty"`
+	RepositoryName    *string                    `json:"repository_name,omitempty"`
+	Number            *int                       `json:"number,omitempty"`
+}
+
+// ListPullRequestsDTO holds the query params of /pullRequest/list.
+// Empty strings and nil times are not filtered on.
+type ListPullRequestsDTO struct {
+	Status         string
+	AuthorId       string
+	TeamName       string
+	RepositoryName string
+	ReviewerId     string
+	Label          string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	MergedFrom     *time.Time
+	MergedTo       *time.Time
+	SortBy         string
+	Order          string
+	Limit          int
+	Cursor         string
+}
+
+type PullRequestListDTO struct {
+	PullRequests []PullRequestDTO `json:"pull_requests"`
+	NextCursor   *string          `json:"next_cursor,omitempty"`
+}
+
+type FallbackReviewerDTO struct {
+	UserId   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+type ReviewerReasonDTO struct {
+	UserId   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Reason   string `json:"reason"`
+}
+
+type PoolConstraintDTO struct {
+	Reason  string   `json:"reason"`
+	UserIds []string `json:"user_ids"`
+}
+
+type AssignmentExplanationDTO struct {
+	Strategy    string              `json:"strategy"`
+	PoolSize    int                 `json:"pool_size"`
+	Reviewers   []ReviewerReasonDTO `json:"reviewers"`
+	Constraints []PoolConstraintDTO `json:"constraints,omitempty"`
 }
 
 type PullRequestResponseDTO struct {
-	PullRequest PullRequestDTO `json:"pr"`
-	ReplacedBy  *string        `json:"replaced_by,omitempty"`
+	PullRequest       PullRequestDTO            `json:"pr"`
+	ReplacedBy        *string                   `json:"replaced_by,omitempty"`
+	FallbackReviewers []FallbackReviewerDTO     `json:"fallback_reviewers,omitempty"`
+	AssignmentId      *int64                    `json:"assignment_id,omitempty"`
+	Assignment        *AssignmentExplanationDTO `json:"assignment,omitempty"`
+}
+
+type AssignmentPreviewRequestDTO struct {
+	AuthorId       string   `json:"author_id"`
+	ChangedPaths   []string `json:"changed_paths,omitempty"`
+	Labels         []string `json:"labels,omitempty"`
+	RepositoryName *string  `json:"repository_name,omitempty"`
+}
+
+type PreviewCandidateDTO struct {
+	UserId         string `json:"user_id"`
+	TeamName       string `json:"team_name"`
+	OpenReviews    int    `json:"open_reviews"`
+	RecentReviews  int    `json:"recent_reviews"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty"`
+}
+
+type ExcludedCandidateDTO struct {
+	UserId   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Reason   string `json:"reason"`
+}
+
+type AssignmentPreviewDTO struct {
+	AuthorId          string                 `json:"author_id"`
+	TeamName          string                 `json:"team_name"`
+	MinReviewers      int                    `json:"min_reviewers"`
+	MaxReviewers      int                    `json:"max_reviewers"`
+	Eligible          []PreviewCandidateDTO  `json:"eligible"`
+	Excluded          []ExcludedCandidateDTO `json:"excluded"`
+	ProposedReviewers []string               `json:"proposed_reviewers"`
+	FallbackReviewers []FallbackReviewerDTO  `json:"fallback_reviewers,omitempty"`
+	Steps             []AssignmentStep       `json:"steps"`
+}
+
+type AssignmentDTO struct {
+	AssignmentId int64            `json:"assignment_id"`
+	Kind         string           `json:"kind"`
+	Seed         uint64           `json:"seed,string"`
+	Reviewers    []string         `json:"reviewers"`
+	Steps        []AssignmentStep `json:"steps"`
+	CreatedAt    string           `json:"created_at"`
+}
+
+type PullRequestAssignmentsDTO struct {
+	PullRequestId string          `json:"pull_request_id"`
+	Assignments   []AssignmentDTO `json:"assignments"`
+}
+
+type AssignmentReplayDTO struct {
+	AssignmentId      int64    `json:"assignment_id"`
+	PullRequestId     string   `json:"pull_request_id"`
+	Seed              uint64   `json:"seed,string"`
+	Reviewers         []string `json:"reviewers"`
+	ReplayedReviewers []string `json:"replayed_reviewers"`
+	Matches           bool     `json:"matches"`
+}
+
+type RebalanceTeamDTO struct {
+	TeamName string `json:"team_name"`
+	DryRun   bool   `json:"dry_run"`
+	ActorId  string `json:"actor_id,omitempty"`
+}
+
+type ReviewMoveDTO struct {
+	PullRequestId string `json:"pull_request_id"`
+	FromUserId    string `json:"from_user_id"`
+	ToUserId      string `json:"to_user_id"`
+}
+
+type RebalanceTeamResultDTO struct {
+	TeamName string          `json:"team_name"`
+	DryRun   bool            `json:"dry_run"`
+	Moves    []ReviewMoveDTO `json:"moves"`
+	Loads    []UserStatsDTO  `json:"loads"`
 }
 
 type UserStatsDTO struct {
@@ -5,6 +5,96 @@ import "time"
 const (
 	StatusOpen   = "OPEN"
 	StatusMerged = "MERGED"
+	StatusClosed = "CLOSED"
+	StatusDraft  = "DRAFT"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+	StrategyWeighted    = "weighted"
+	// StrategyRecentHistory weights candidates by the reviews they completed
+	// within the review window of the team.
+	StrategyRecentHistory = "recent_history"
+
+	DefaultReviewerStrategy = StrategyLeastLoaded
+)
+
+const (
+	RuleExclude = "EXCLUDE"
+	RulePrefer  = "PREFER"
+)
+
+const (
+	AbsenceVacation = "VACATION"
+	AbsenceDayOff   = "DAY_OFF"
+)
+
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+)
+
+// Kinds of PR history events.
+const (
+	EventStatusChanged   = "STATUS_CHANGED"
+	EventReviewerAdded   = "REVIEWER_ADDED"
+	EventReviewerRemoved = "REVIEWER_REMOVED"
+	EventUpdated         = "UPDATED"
+)
+
+// Fields of a PR reported by EventUpdated.
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldLabels      = "labels"
+	FieldParent      = "parent"
+)
+
+// Keys PR lists can be sorted by.
+const (
+	SortCreatedAt = "created_at"
+	SortName      = "name"
+)
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+const (
+	AssignmentCreate   = "CREATE"
+	AssignmentReassign = "REASSIGN"
+)
+
+// Reasons a team member is left out of the reviewer candidate pool.
+const (
+	ExcludedAuthor     = "AUTHOR"
+	ExcludedInactive   = "INACTIVE"
+	ExcludedAbsent     = "ABSENT"
+	ExcludedByRule     = "RULE"
+	ExcludedAtCapacity = "AT_CAPACITY"
+)
+
+// Reasons a reviewer was chosen.
+const (
+	ReasonOwnershipMatch = "OWNERSHIP_MATCH"
+	ReasonSkillMatch     = "SKILL_MATCH"
+	ReasonPreferred      = "PREFERRED"
+	ReasonRandomPick     = "RANDOM_PICK"
+	ReasonRoundRobin     = "ROUND_ROBIN"
+	ReasonLeastLoaded    = "LEAST_LOADED"
+	ReasonWeightedPick   = "WEIGHTED_PICK"
+	ReasonRecentHistory  = "RECENT_HISTORY"
+	ReasonManual         = "MANUAL"
+)
+
+const (
+	DefaultMinReviewers = 0
+	DefaultMaxReviewers = 2
+
+	DefaultReviewWindowDays = 14
 )
 
 type User struct {
@@ -12,10 +102,80 @@ type User struct {
 	Username string
 	TeamName string
 	IsActive bool
+	// MaxOpenReviews overrides the team limit of open reviews when set.
+	MaxOpenReviews *int
+}
+
+type UserSkill struct {
+	UserId string
+	Skill  string
+}
+
+type PullRequestReviewer struct {
+	PullRequestId string
+	UserId        string
+}
+
+type PullRequestLabel struct {
+	PullRequestId string
+	Label         string
+}
+
+// Absence is a period when a user can't review: a vacation between
+// StartsAt and EndsAt or a weekly day off on Weekday (1 is Monday, 7 is
+// Sunday, in UTC).
+type Absence struct {
+	Id       int64
+	UserId   string
+	Kind     string
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	Weekday  *int
+	Reason   string
 }
 
 type Team struct {
-	TeamName string
+	TeamName         string
+	ReviewerStrategy string
+	MinReviewers     int
+	MaxReviewers     int
+	// MaxOpenReviews is the default limit of open reviews of a member,
+	// nil means unlimited.
+	MaxOpenReviews *int
+	// ReviewWindowDays is the period completed reviews are counted over.
+	ReviewWindowDays int
+	// RequiredApprovals is the number of approvals a PR of the team needs
+	// to be merged.
+	RequiredApprovals int
+	// RequireResolvedComments blocks merging PRs of the team while they
+	// have unresolved comment threads.
+	RequireResolvedComments bool
+	// RoundRobinCursor is the member the round robin strategy picked last,
+	// empty before the first pick.
+	RoundRobinCursor string
+}
+
+// Repository is a code repository owned by a team. PRs of a repository
+// are reviewed by the owning team instead of the author's one when
+// RouteReviewsToOwner is set.
+type Repository struct {
+	RepositoryName      string
+	TeamName            string
+	RouteReviewsToOwner bool
+}
+
+type CodeOwnerRule struct {
+	TeamName  string
+	Position  int
+	Pattern   string
+	UserIds   []string
+	TeamNames []string
+}
+
+type ReviewerRule struct {
+	AuthorId   string
+	ReviewerId string
+	Kind       string
 }
 
 type PullRequestUser struct {
@@ -23,16 +183,97 @@ type PullRequestUser struct {
 	PullRequestId string
 }
 
+// Review is the latest decision of a reviewer on a PR.
+type Review struct {
+	PullRequestId string
+	UserId        string
+	Decision      string
+	Comment       string
+	SubmittedAt   time.Time
+}
+
+// Comment is a comment on a PR. A comment without ParentId starts a
+// thread, replies always point to the first comment of their thread.
+// Only threads are resolved.
+type Comment struct {
+	Id            int64
+	PullRequestId string
+	ParentId      *int64
+	AuthorId      string
+	Body          string
+	ResolvedBy    *string
+	ResolvedAt    *time.Time
+	CreatedAt     time.Time
+}
+
+// PullRequest is stacked on the PR ParentId points to, if any. A PR of a
+// repository has a Number unique within RepositoryName. ClosedAt is set
+// only while the PR is closed.
 type PullRequest struct {
 	Id              string
 	PullRequestName string
+	Description     string
 	AuthorId        string
 	Status          string
+	ParentId        *string
+	RepositoryName  *string
+	Number          *int
+	CreatedAt       time.Time
+	MergedAt        *time.Time
+	ClosedAt        *time.Time
 	UpdatedAt       *time.Time
 }
 
+// PullRequestEvent is a status, reviewer or field change of a PR. Fields
+// lists the fields changed by an EventUpdated. ActorId is nil
+// when the change wasn't requested by a user, e.g. when reviews of a
+// deactivated user are reassigned.
+type PullRequestEvent struct {
+	Id            int64
+	PullRequestId string
+	Kind          string
+	ActorId       *string
+	UserId        *string
+	FromStatus    *string
+	ToStatus      *string
+	Fields        []string
+	CreatedAt     time.Time
+}
+
+type AssignmentCandidate struct {
+	UserId        string `json:"user_id"`
+	OpenReviews   int    `json:"open_reviews"`
+	RecentReviews int    `json:"recent_reviews,omitempty"`
+}
+
+// AssignmentStep is a single call of a reviewer selector, stored with
+// everything needed to repeat it.
+type AssignmentStep struct {
+	TeamName   string                `json:"team_name"`
+	Strategy   string                `json:"strategy"`
+	Cursor     string                `json:"cursor,omitempty"`
+	Count      int                   `json:"count"`
+	Candidates []AssignmentCandidate `json:"candidates"`
+	Picked     []string              `json:"picked"`
+}
+
+type Assignment struct {
+	Id            int64
+	PullRequestId string
+	Kind          string
+	Seed          uint64
+	Steps         []AssignmentStep
+	Reviewers     []string
+	CreatedAt     *time.Time
+}
+
 type UserStats struct {
 	Id                    string
 	Username              string
 	OpenPullRequestsCount int
+	// MaxOpenReviews is the effective limit of the user, nil if unlimited.
+	MaxOpenReviews *int
+	// RecentReviewsCount is the number of reviews completed within the
+	// review window of the user team.
+	RecentReviewsCount int
 }
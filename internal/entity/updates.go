@@ -1,7 +1,59 @@
 package entity
 
+import "time"
+
+// TeamUpdate changes the settings that are set.
+type TeamUpdate struct {
+	ReviewerStrategy *string
+	MinReviewers     *int
+	MaxReviewers     *int
+	MaxOpenReviews   *int
+	// ClearMaxOpenReviews removes the default limit of open reviews of
+	// members, MaxOpenReviews is ignored then.
+	ClearMaxOpenReviews     bool
+	ReviewWindowDays        *int
+	RequiredApprovals       *int
+	RequireResolvedComments *bool
+}
+
 type UserUpdate struct {
 	Username *string
 	TeamName *string
 	IsActive *bool
 }
+
+// PullRequestUpdate changes the fields that are set. An empty ParentId
+// unstacks the PR.
+type PullRequestUpdate struct {
+	PullRequestName *string
+	Description     *string
+	ParentId        *string
+}
+
+// PullRequestFilter selects PRs for a list page. Nil fields are not
+// filtered on, ranges include From and exclude To. PRs are ordered by
+// SortBy and id, After is the last PR of the previous page.
+type PullRequestFilter struct {
+	Status         *string
+	AuthorId       *string
+	TeamName       *string
+	RepositoryName *string
+	ReviewerId     *string
+	Label          *string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	MergedFrom     *time.Time
+	MergedTo       *time.Time
+	SortBy         string
+	Descending     bool
+	After          *PullRequestCursor
+	Limit          int
+}
+
+type PullRequestCursor struct {
+	CreatedAt       time.Time `json:"created_at"`
+	PullRequestName string    `json:"name"`
+	Id              string    `json:"id"`
+	SortBy          string    `json:"sort"`
+	Order           string    `json:"order"`
+}
@@ -1,12 +1,21 @@
 package codes
 
 const (
-	Internal          = "INTERNAL"
-	NotFound          = "NOT_FOUND"
-	BadRequest        = "BAD_REQUEST"
-	TeamExists        = "TEAM_EXISTS"
-	PullRequestExists = "PR_EXISTS"
-	PullRequestMerged = "PR_MERGED"
-	NotAssigned       = "NOT_ASSIGNED"
-	NoCandidate       = "NO_CANDIDATE"
+	Internal           = "INTERNAL"
+	NotFound           = "NOT_FOUND"
+	BadRequest         = "BAD_REQUEST"
+	TeamExists         = "TEAM_EXISTS"
+	PullRequestExists  = "PR_EXISTS"
+	PullRequestMerged  = "PR_MERGED"
+	PullRequestClosed  = "PR_CLOSED"
+	PullRequestDraft   = "PR_DRAFT"
+	NotDraft           = "NOT_DRAFT"
+	NotAssigned        = "NOT_ASSIGNED"
+	NotParticipant     = "NOT_PARTICIPANT"
+	NoCandidate        = "NO_CANDIDATE"
+	NotEnoughReviewers = "NOT_ENOUGH_REVIEWERS"
+	AtCapacity         = "AT_CAPACITY"
+	InvalidReviewer    = "INVALID_REVIEWER"
+	MergeBlocked       = "MERGE_BLOCKED"
+	DependencyCycle    = "DEPENDENCY_CYCLE"
 )
@@ -13,11 +13,79 @@ var ErrBaseAlreadyExists = errors.New("already exists")
 
 var ErrUserNotAssigned = errors.New("reviewer is not assigned to this PR")
 var ErrNoActiveUsers = errors.New("no active replacement candidate in team")
+var ErrNotEnoughReviewers = errors.New("not enough active reviewer candidates in team")
+var ErrReviewersAtCapacity = errors.New("all reviewer candidates are at capacity")
 var ErrReassignOnMergedPR = errors.New("cannot reassign on merged PR")
+var ErrReassignOnClosedPR = errors.New("cannot reassign on closed PR")
+var ErrMergeClosedPR = errors.New("cannot merge closed PR")
+var ErrCloseMergedPR = errors.New("cannot close or reopen merged PR")
+var ErrDraftPR = errors.New("operation is not allowed on draft PR")
+var ErrNotDraftPR = errors.New("PR is not a draft")
+var ErrReviewOnMergedPR = errors.New("cannot review merged PR")
+var ErrReviewOnClosedPR = errors.New("cannot review closed PR")
+var ErrUpdateMergedPR = errors.New("cannot update merged PR")
+var ErrCommentOnMergedPR = errors.New("cannot comment on merged PR")
+var ErrDependencyCycle = errors.New("PR dependencies would form a cycle")
+var ErrNotParticipant = errors.New("user is neither the author nor an assigned reviewer of the PR")
+
+var ErrBaseMergeBlocked = errors.New("merge is blocked")
+var ErrNotEnoughApprovals = fmt.Errorf("%w: not enough approvals", ErrBaseMergeBlocked)
+var ErrChangesRequested = fmt.Errorf("%w: changes are requested", ErrBaseMergeBlocked)
+var ErrParentNotMerged = fmt.Errorf("%w: a parent PR is not merged", ErrBaseMergeBlocked)
+var ErrUnresolvedComments = fmt.Errorf(
+	"%w: there are unresolved comment threads",
+	ErrBaseMergeBlocked,
+)
+
+var ErrBaseInvalidReviewer = errors.New("invalid reviewer")
+var ErrReviewerInactive = fmt.Errorf("%w: user is not active", ErrBaseInvalidReviewer)
+var ErrReviewerIsAuthor = fmt.Errorf("%w: user is the author of the PR", ErrBaseInvalidReviewer)
+var ErrReviewerAlreadyAssigned = fmt.Errorf(
+	"%w: user is already assigned to the PR",
+	ErrBaseInvalidReviewer,
+)
+var ErrReviewerAbsent = fmt.Errorf("%w: user is absent", ErrBaseInvalidReviewer)
+var ErrReviewerAtCapacity = fmt.Errorf(
+	"%w: user is at the open reviews limit",
+	ErrBaseInvalidReviewer,
+)
+var ErrReviewerExcluded = fmt.Errorf(
+	"%w: user is excluded by a reviewer rule of the author",
+	ErrBaseInvalidReviewer,
+)
 
 var ErrTeamAlreadyExists = fmt.Errorf("team %w", ErrBaseAlreadyExists)
 var ErrPullRequestAlreadyExists = fmt.Errorf("pull request %w", ErrBaseAlreadyExists)
 
+var ErrUnknownReviewerStrategy = fmt.Errorf("unknown reviewer strategy: %w", ErrBaseBadRequest)
+var ErrInvalidReviewerCount = fmt.Errorf("invalid reviewer count: %w", ErrBaseBadRequest)
+var ErrInvalidFallbackTeams = fmt.Errorf("invalid fallback teams: %w", ErrBaseBadRequest)
+var ErrInvalidCodeOwnerPattern = fmt.Errorf("invalid code owner pattern: %w", ErrBaseBadRequest)
+var ErrInvalidCodeOwnerRule = fmt.Errorf("code owner rule must have owners: %w", ErrBaseBadRequest)
+var ErrInvalidChangedPath = fmt.Errorf("invalid changed path: %w", ErrBaseBadRequest)
+var ErrInvalidReviewerRule = fmt.Errorf("invalid reviewer rule: %w", ErrBaseBadRequest)
+var ErrInvalidTag = fmt.Errorf("invalid skill or label: %w", ErrBaseBadRequest)
+var ErrInvalidAbsence = fmt.Errorf("invalid absence: %w", ErrBaseBadRequest)
+var ErrInvalidMaxOpenReviews = fmt.Errorf("invalid open reviews limit: %w", ErrBaseBadRequest)
+var ErrInvalidReviewWindow = fmt.Errorf("invalid review window: %w", ErrBaseBadRequest)
+var ErrInvalidRequiredApprovals = fmt.Errorf("invalid required approvals: %w", ErrBaseBadRequest)
+var ErrInvalidReview = fmt.Errorf("invalid review: %w", ErrBaseBadRequest)
+var ErrInvalidPullRequestName = fmt.Errorf("invalid pull request name: %w", ErrBaseBadRequest)
+var ErrInvalidDescription = fmt.Errorf("invalid description: %w", ErrBaseBadRequest)
+var ErrEmptyPullRequestUpdate = fmt.Errorf("nothing to update: %w", ErrBaseBadRequest)
+var ErrEmptyTeamUpdate = fmt.Errorf("no team settings to update: %w", ErrBaseBadRequest)
+var ErrInvalidComment = fmt.Errorf("invalid comment: %w", ErrBaseBadRequest)
+var ErrInvalidRepository = fmt.Errorf("invalid repository: %w", ErrBaseBadRequest)
+var ErrInvalidPullRequestNumber = fmt.Errorf("invalid pull request number: %w", ErrBaseBadRequest)
+var ErrRepositoryPullRequestId = fmt.Errorf(
+	"pull request id is derived from repository and number: %w",
+	ErrBaseBadRequest,
+)
+var ErrInvalidPullRequestId = fmt.Errorf(
+	"pull request id must not contain '#' outside of a repository: %w",
+	ErrBaseBadRequest,
+)
+
 func ErrNotFound(entity string, param string, value any) error {
 	return fmt.Errorf("%s with %s: %v %w", entity, param, value, ErrBaseNotFound)
 }
@@ -3,8 +3,11 @@ package handler
 import (
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/service"
 )
 
@@ -36,6 +39,86 @@ func (h *PullRequestHandler) GetOpenPullRequestsByReviewers(w http.ResponseWrite
 	WriteJsonDTO(w, http.StatusOK, res)
 }
 
+func (h *PullRequestHandler) GetPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GetPullRequest", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	query := r.URL.Query()
+	prId := query.Get("pull_request_id")
+	repositoryName := query.Get("repository_name")
+	number, numberErr := strconv.Atoi(query.Get("number"))
+	if prId == "" && (repositoryName == "" || numberErr != nil) {
+		h.logger.Debug("GetPullRequest: query param not found")
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	var res *entity.PullRequestResponseDTO
+	var err error
+	if prId != "" {
+		res, err = h.srv.GetPullRequest(r.Context(), prId)
+	} else {
+		res, err = h.srv.GetPullRequestByNumber(r.Context(), repositoryName, number)
+	}
+	if err != nil {
+		h.logger.Debug("GetPullRequest failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) ListPullRequests(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("ListPullRequests", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	query := r.URL.Query()
+	data := entity.ListPullRequestsDTO{
+		Status:         query.Get("status"),
+		AuthorId:       query.Get("author_id"),
+		TeamName:       query.Get("team_name"),
+		RepositoryName: query.Get("repository_name"),
+		ReviewerId:     query.Get("reviewer_id"),
+		Label:          query.Get("label"),
+		SortBy:         query.Get("sort"),
+		Order:          query.Get("order"),
+		Cursor:         query.Get("cursor"),
+	}
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		data.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			h.logger.Debug("ListPullRequests: invalid limit", "err", err)
+			WriteError(w, errs.ErrBaseBadFilter)
+			return
+		}
+	}
+	for param, dst := range map[string]**time.Time{
+		"created_from": &data.CreatedFrom,
+		"created_to":   &data.CreatedTo,
+		"merged_from":  &data.MergedFrom,
+		"merged_to":    &data.MergedTo,
+	} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.logger.Debug("ListPullRequests: invalid time", "param", param, "err", err)
+			WriteError(w, errs.ErrBaseBadFilter)
+			return
+		}
+		*dst = &t
+	}
+
+	res, err := h.srv.ListPullRequests(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("ListPullRequests failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
 func (h *PullRequestHandler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("CreatePullRequest", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
 	data := entity.PullRequestCreateDTO{}
@@ -51,6 +134,21 @@ func (h *PullRequestHandler) CreatePullRequest(w http.ResponseWriter, r *http.Re
 	WriteJsonDTO(w, http.StatusCreated, res)
 }
 
+func (h *PullRequestHandler) PreviewAssignment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("PreviewAssignment", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.AssignmentPreviewRequestDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.PreviewAssignment(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("PreviewAssignment failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
 func (h *PullRequestHandler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("MergePullRequest", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
 	data := entity.MergePullRequestDTO{}
@@ -66,6 +164,130 @@ func (h *PullRequestHandler) MergePullRequest(w http.ResponseWriter, r *http.Req
 	WriteJsonDTO(w, http.StatusOK, res)
 }
 
+func (h *PullRequestHandler) UpdatePullRequest(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UpdatePullRequest", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.UpdatePullRequestDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.UpdatePullRequest(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("UpdatePullRequest failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SubmitReview", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.SubmitReviewDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.SubmitReview(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("SubmitReview failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) AddComment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("AddComment", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.AddCommentDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.AddComment(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("AddComment failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusCreated, res)
+}
+
+func (h *PullRequestHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GetComments", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	prId := r.URL.Query().Get("pull_request_id")
+	if prId == "" {
+		h.logger.Debug("GetComments: query param not found")
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	res, err := h.srv.GetComments(r.Context(), prId)
+	if err != nil {
+		h.logger.Debug("GetComments failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) ResolveComment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("ResolveComment", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.ResolveCommentDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.ResolveComment(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("ResolveComment failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) MarkReadyPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("MarkReadyPullRequest", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.MarkReadyPullRequestDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.MarkReadyPullRequest(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("MarkReadyPullRequest failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) ClosePullRequest(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("ClosePullRequest", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.ClosePullRequestDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.ClosePullRequest(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("ClosePullRequest failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) ReopenPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("ReopenPullRequest", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.ReopenPullRequestDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.ReopenPullRequest(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("ReopenPullRequest failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
 func (h *PullRequestHandler) ReassignPullRequest(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("ReassignPullRequest", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
 	data := entity.ReassignPullRequestDTO{}
@@ -80,3 +302,106 @@ func (h *PullRequestHandler) ReassignPullRequest(w http.ResponseWriter, r *http.
 
 	WriteJsonDTO(w, http.StatusOK, res)
 }
+
+func (h *PullRequestHandler) AddReviewer(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("AddReviewer", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.PullRequestReviewerDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.AddReviewer(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("AddReviewer failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) RemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("RemoveReviewer", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.PullRequestReviewerDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.RemoveReviewer(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("RemoveReviewer failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) RebalanceTeam(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("RebalanceTeam", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.RebalanceTeamDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.RebalanceTeam(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("RebalanceTeam failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) GetPullRequestHistory(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GetPullRequestHistory", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	prId := r.URL.Query().Get("pull_request_id")
+	if prId == "" {
+		h.logger.Debug("GetPullRequestHistory: query param not found")
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	res, err := h.srv.GetPullRequestHistory(r.Context(), prId)
+	if err != nil {
+		h.logger.Debug("GetPullRequestHistory failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) GetAssignments(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GetAssignments", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	prId := r.URL.Query().Get("pull_request_id")
+	if prId == "" {
+		h.logger.Debug("GetAssignments: query param not found")
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	res, err := h.srv.GetAssignments(r.Context(), prId)
+	if err != nil {
+		h.logger.Debug("GetAssignments failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *PullRequestHandler) ReplayAssignment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("ReplayAssignment", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	prId := r.URL.Query().Get("pull_request_id")
+	assignmentId, err := strconv.ParseInt(r.URL.Query().Get("assignment_id"), 10, 64)
+	if prId == "" || err != nil {
+		h.logger.Debug("ReplayAssignment: invalid query params", "err", err)
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	res, err := h.srv.ReplayAssignment(r.Context(), prId, assignmentId)
+	if err != nil {
+		h.logger.Debug("ReplayAssignment failed", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
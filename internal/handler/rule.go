@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/service"
+)
+
+type ReviewerRuleHandler struct {
+	logger *slog.Logger
+	srv    service.BaseReviewerRuleService
+}
+
+func NewReviewerRuleHandler(
+	baseLogger *slog.Logger,
+	srv service.BaseReviewerRuleService,
+) *ReviewerRuleHandler {
+	logger := baseLogger.With("module", "rulehandler")
+	return &ReviewerRuleHandler{
+		logger: logger,
+		srv:    srv,
+	}
+}
+
+func (h *ReviewerRuleHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GetRules", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	authorId := r.URL.Query().Get("author_id")
+	if authorId == "" {
+		h.logger.Debug("GetRules: query param not found")
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	res, err := h.srv.GetRules(r.Context(), authorId)
+	if err != nil {
+		h.logger.Debug("GetRules", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *ReviewerRuleHandler) SetRule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SetRule", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.ReviewerRuleDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.SetRule(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("SetRule", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *ReviewerRuleHandler) RemoveRule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("RemoveRule", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.RemoveReviewerRuleDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.RemoveRule(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("RemoveRule", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
@@ -55,3 +55,101 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 
 	WriteJsonDTO(w, http.StatusOK, res)
 }
+
+func (h *TeamHandler) SetTeamSettings(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SetTeamSettings", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.TeamSettingsDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.SetTeamSettings(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("SetTeamSettings", "error", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *TeamHandler) SetFallbackTeams(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SetFallbackTeams", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.SetFallbackTeamsDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.SetFallbackTeams(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("SetFallbackTeams", "error", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *TeamHandler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GetCodeOwners", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		h.logger.Debug("GetCodeOwners: query param not found")
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	res, err := h.srv.GetCodeOwners(r.Context(), teamName)
+	if err != nil {
+		h.logger.Debug("GetCodeOwners", "error", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *TeamHandler) SetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SetCodeOwners", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.TeamCodeOwnersDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.SetCodeOwners(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("SetCodeOwners", "error", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *TeamHandler) GetRepositories(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GetRepositories", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		h.logger.Debug("GetRepositories: query param not found")
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	res, err := h.srv.GetRepositories(r.Context(), teamName)
+	if err != nil {
+		h.logger.Debug("GetRepositories", "error", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *TeamHandler) SetRepository(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SetRepository", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.RepositoryDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.SetRepository(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("SetRepository", "error", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
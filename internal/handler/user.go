@@ -37,6 +37,36 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	WriteJsonDTO(w, http.StatusOK, res)
 }
 
+func (h *UserHandler) SetSkills(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SetSkills", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.SetUserSkillsDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.SetSkills(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("SetSkills", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *UserHandler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SetMaxOpenReviews", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.SetUserMaxOpenReviewsDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.SetMaxOpenReviews(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("SetMaxOpenReviews", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
 func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("GetReview", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
 	userId := r.URL.Query().Get("user_id")
@@ -55,3 +85,52 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 
 	WriteJsonDTO(w, http.StatusOK, res)
 }
+
+func (h *UserHandler) AddAbsence(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("AddAbsence", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.AbsenceDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.AddAbsence(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("AddAbsence", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusCreated, res)
+}
+
+func (h *UserHandler) GetAbsences(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GetAbsences", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	userId := r.URL.Query().Get("user_id")
+	if userId == "" {
+		h.logger.Debug("GetAbsences: query param not found")
+		WriteError(w, errs.ErrBaseBadFilter)
+		return
+	}
+
+	res, err := h.srv.GetAbsences(r.Context(), userId)
+	if err != nil {
+		h.logger.Debug("GetAbsences", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
+
+func (h *UserHandler) CancelAbsence(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CancelAbsence", "ip", r.RemoteAddr, "user-agent", r.UserAgent())
+	data := entity.CancelAbsenceDTO{}
+	DecodeDTOFromJson(w, r, &data)
+
+	res, err := h.srv.CancelAbsence(r.Context(), data)
+	if err != nil {
+		h.logger.Debug("CancelAbsence", "err", err)
+		WriteError(w, err)
+		return
+	}
+
+	WriteJsonDTO(w, http.StatusOK, res)
+}
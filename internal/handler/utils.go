@@ -35,18 +35,124 @@ func mapToErrorDTO(err error) entity.ErrorDTO {
 			Message: errs.ErrUserNotAssigned.Error(),
 		}
 	}
+	if errors.Is(err, errs.ErrNotParticipant) {
+		return entity.ErrorDTO{
+			Code:    codes.NotParticipant,
+			Message: errs.ErrNotParticipant.Error(),
+		}
+	}
 	if errors.Is(err, errs.ErrNoActiveUsers) {
 		return entity.ErrorDTO{
 			Code:    codes.NoCandidate,
 			Message: errs.ErrNoActiveUsers.Error(),
 		}
 	}
+	if errors.Is(err, errs.ErrNotEnoughReviewers) {
+		return entity.ErrorDTO{
+			Code:    codes.NotEnoughReviewers,
+			Message: errs.ErrNotEnoughReviewers.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrReviewersAtCapacity) {
+		return entity.ErrorDTO{
+			Code:    codes.AtCapacity,
+			Message: errs.ErrReviewersAtCapacity.Error(),
+		}
+	}
+	for _, invalid := range []error{
+		errs.ErrReviewerInactive,
+		errs.ErrReviewerIsAuthor,
+		errs.ErrReviewerAlreadyAssigned,
+		errs.ErrReviewerAbsent,
+		errs.ErrReviewerAtCapacity,
+		errs.ErrReviewerExcluded,
+	} {
+		if errors.Is(err, invalid) {
+			return entity.ErrorDTO{
+				Code:    codes.InvalidReviewer,
+				Message: invalid.Error(),
+			}
+		}
+	}
+	for _, blocked := range []error{
+		errs.ErrNotEnoughApprovals,
+		errs.ErrChangesRequested,
+		errs.ErrParentNotMerged,
+		errs.ErrUnresolvedComments,
+	} {
+		if errors.Is(err, blocked) {
+			return entity.ErrorDTO{
+				Code:    codes.MergeBlocked,
+				Message: blocked.Error(),
+			}
+		}
+	}
+	if errors.Is(err, errs.ErrDependencyCycle) {
+		return entity.ErrorDTO{
+			Code:    codes.DependencyCycle,
+			Message: errs.ErrDependencyCycle.Error(),
+		}
+	}
 	if errors.Is(err, errs.ErrReassignOnMergedPR) {
 		return entity.ErrorDTO{
 			Code:    codes.PullRequestMerged,
 			Message: errs.ErrReassignOnMergedPR.Error(),
 		}
 	}
+	if errors.Is(err, errs.ErrCloseMergedPR) {
+		return entity.ErrorDTO{
+			Code:    codes.PullRequestMerged,
+			Message: errs.ErrCloseMergedPR.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrReassignOnClosedPR) {
+		return entity.ErrorDTO{
+			Code:    codes.PullRequestClosed,
+			Message: errs.ErrReassignOnClosedPR.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrMergeClosedPR) {
+		return entity.ErrorDTO{
+			Code:    codes.PullRequestClosed,
+			Message: errs.ErrMergeClosedPR.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrUpdateMergedPR) {
+		return entity.ErrorDTO{
+			Code:    codes.PullRequestMerged,
+			Message: errs.ErrUpdateMergedPR.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrCommentOnMergedPR) {
+		return entity.ErrorDTO{
+			Code:    codes.PullRequestMerged,
+			Message: errs.ErrCommentOnMergedPR.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrReviewOnMergedPR) {
+		return entity.ErrorDTO{
+			Code:    codes.PullRequestMerged,
+			Message: errs.ErrReviewOnMergedPR.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrReviewOnClosedPR) {
+		return entity.ErrorDTO{
+			Code:    codes.PullRequestClosed,
+			Message: errs.ErrReviewOnClosedPR.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrDraftPR) {
+		return entity.ErrorDTO{
+			Code:    codes.PullRequestDraft,
+			Message: errs.ErrDraftPR.Error(),
+		}
+	}
+	if errors.Is(err, errs.ErrNotDraftPR) {
+		return entity.ErrorDTO{
+			Code:    codes.NotDraft,
+			Message: errs.ErrNotDraftPR.Error(),
+		}
+	}
 	if errors.Is(err, errs.ErrBaseInternal) {
 		return entity.ErrorDTO{
 			Code:    codes.Internal,
@@ -78,8 +184,23 @@ func mapToHttpStatus(err error) int {
 	if errors.Is(err, errs.ErrTeamAlreadyExists) ||
 		errors.Is(err, errs.ErrPullRequestAlreadyExists) ||
 		errors.Is(err, errs.ErrUserNotAssigned) ||
+		errors.Is(err, errs.ErrNotParticipant) ||
 		errors.Is(err, errs.ErrNoActiveUsers) ||
-		errors.Is(err, errs.ErrReassignOnMergedPR) {
+		errors.Is(err, errs.ErrNotEnoughReviewers) ||
+		errors.Is(err, errs.ErrReviewersAtCapacity) ||
+		errors.Is(err, errs.ErrBaseInvalidReviewer) ||
+		errors.Is(err, errs.ErrReassignOnMergedPR) ||
+		errors.Is(err, errs.ErrReassignOnClosedPR) ||
+		errors.Is(err, errs.ErrMergeClosedPR) ||
+		errors.Is(err, errs.ErrCloseMergedPR) ||
+		errors.Is(err, errs.ErrDraftPR) ||
+		errors.Is(err, errs.ErrNotDraftPR) ||
+		errors.Is(err, errs.ErrUpdateMergedPR) ||
+		errors.Is(err, errs.ErrCommentOnMergedPR) ||
+		errors.Is(err, errs.ErrDependencyCycle) ||
+		errors.Is(err, errs.ErrReviewOnMergedPR) ||
+		errors.Is(err, errs.ErrReviewOnClosedPR) ||
+		errors.Is(err, errs.ErrBaseMergeBlocked) {
 		return http.StatusConflict
 	}
 	if errors.Is(err, errs.ErrBaseBadFilter) ||
@@ -20,13 +20,91 @@ type BaseUserRepository interface {
 	GetByTeamName(ctx context.Context, db Querier, teamName string) ([]entity.User, error)
 	GetActiveByTeamName(ctx context.Context, db Querier, teamName string) ([]entity.User, error)
 	GetReviewersByPrId(ctx context.Context, db Querier, prId string) ([]entity.User, error)
+	GetSkillsByUserIds(
+		ctx context.Context,
+		db Querier,
+		userIds []string,
+	) ([]entity.UserSkill, error)
+	GetUserIdsBySkills(ctx context.Context, db Querier, skills []string) ([]string, error)
 	AddUsers(ctx context.Context, db Querier, new []entity.User) error
+	SetSkills(ctx context.Context, db Querier, userId string, skills []string) error
+	SetMaxOpenReviews(ctx context.Context, db Querier, userId string, limit *int) error
 	UpdateUser(ctx context.Context, db Querier, userId string, update *entity.UserUpdate) error
 }
 
+type BaseAbsenceRepository interface {
+	GetAbsencesByUserId(ctx context.Context, db Querier, userId string) ([]entity.Absence, error)
+	AddAbsence(ctx context.Context, db Querier, ent *entity.Absence) error
+	RemoveAbsence(ctx context.Context, db Querier, userId string, id int64) error
+}
+
 type BaseTeamRepository interface {
 	GetTeam(ctx context.Context, db Querier, teamName string) (*entity.Team, error)
 	AddTeam(ctx context.Context, db Querier, new *entity.Team) error
+	UpdateTeam(ctx context.Context, db Querier, teamName string, update *entity.TeamUpdate) error
+	SetRoundRobinCursor(ctx context.Context, db Querier, teamName string, cursor string) error
+	GetFallbackTeams(ctx context.Context, db Querier, teamName string) ([]entity.Team, error)
+	SetFallbackTeams(ctx context.Context, db Querier, teamName string, fallbacks []string) error
+	GetCodeOwnerRules(
+		ctx context.Context,
+		db Querier,
+		teamName string,
+	) ([]entity.CodeOwnerRule, error)
+	SetCodeOwnerRules(
+		ctx context.Context,
+		db Querier,
+		teamName string,
+		rules []entity.CodeOwnerRule,
+	) error
+	GetRepository(
+		ctx context.Context,
+		db Querier,
+		repositoryName string,
+	) (*entity.Repository, error)
+	GetRepositoriesByTeamName(
+		ctx context.Context,
+		db Querier,
+		teamName string,
+	) ([]entity.Repository, error)
+	SetRepository(ctx context.Context, db Querier, repo *entity.Repository) error
+}
+
+type BaseReviewerRuleRepository interface {
+	GetRulesByAuthorId(
+		ctx context.Context,
+		db Querier,
+		authorId string,
+	) ([]entity.ReviewerRule, error)
+	SetRule(ctx context.Context, db Querier, rule *entity.ReviewerRule) error
+	RemoveRule(ctx context.Context, db Querier, authorId string, reviewerId string) error
+}
+
+type BaseAssignmentRepository interface {
+	GetAssignmentById(ctx context.Context, db Querier, id int64) (*entity.Assignment, error)
+	GetAssignmentsByPrId(ctx context.Context, db Querier, prId string) ([]entity.Assignment, error)
+	AddAssignment(ctx context.Context, db Querier, ent *entity.Assignment) error
+}
+
+type BaseReviewRepository interface {
+	GetReviewsByPrId(ctx context.Context, db Querier, prId string) ([]entity.Review, error)
+	GetReviewsByPrIds(ctx context.Context, db Querier, prIds []string) ([]entity.Review, error)
+	SetReview(ctx context.Context, db Querier, review *entity.Review) error
+}
+
+type BaseCommentRepository interface {
+	GetCommentById(ctx context.Context, db Querier, commentId int64) (*entity.Comment, error)
+	GetCommentsByPrId(ctx context.Context, db Querier, prId string) ([]entity.Comment, error)
+	CountUnresolvedThreads(ctx context.Context, db Querier, prId string) (int, error)
+	// CountUnresolvedThreadsByPrIds maps ids of PRs with unresolved
+	// threads to their number.
+	CountUnresolvedThreadsByPrIds(
+		ctx context.Context,
+		db Querier,
+		prIds []string,
+	) (map[string]int, error)
+
+	AddComment(ctx context.Context, db Querier, comment *entity.Comment) error
+	ResolveComment(ctx context.Context, db Querier, commentId int64, userId string) error
 }
 
 type BasePullRequestRepository interface {
@@ -36,10 +114,67 @@ type BasePullRequestRepository interface {
 		reviewerId string,
 	) ([]entity.PullRequest, error)
 	GetPullRequestById(ctx context.Context, db Querier, prId string) (*entity.PullRequest, error)
+	// GetPullRequestByIdForUpdate also locks the row of the PR until the
+	// end of the transaction of db.
+	GetPullRequestByIdForUpdate(
+		ctx context.Context,
+		db Querier,
+		prId string,
+	) (*entity.PullRequest, error)
+	GetPullRequestByNumber(
+		ctx context.Context,
+		db Querier,
+		repositoryName string,
+		number int,
+	) (*entity.PullRequest, error)
+	ListPullRequests(
+		ctx context.Context,
+		db Querier,
+		filter *entity.PullRequestFilter,
+	) ([]entity.PullRequest, error)
 	GetOpenPullRequestsByReviewers(ctx context.Context, db Querier) ([]entity.UserStats, error)
+	GetOpenPullRequestsByTeamMembers(
+		ctx context.Context,
+		db Querier,
+		teamName string,
+	) ([]entity.UserStats, error)
+	GetOpenPullRequestsByUserIds(
+		ctx context.Context,
+		db Querier,
+		userIds []string,
+	) ([]entity.UserStats, error)
+	GetPullRequestPaths(ctx context.Context, db Querier, prId string) ([]string, error)
+	GetPullRequestLabels(ctx context.Context, db Querier, prId string) ([]string, error)
+	GetLabelsByPrIds(ctx context.Context, db Querier, prIds []string) ([]entity.PullRequestLabel, error)
+	GetReviewersByPrIds(
+		ctx context.Context,
+		db Querier,
+		prIds []string,
+	) ([]entity.PullRequestReviewer, error)
+	GetPullRequestEvents(
+		ctx context.Context,
+		db Querier,
+		prId string,
+	) ([]entity.PullRequestEvent, error)
+	GetPullRequestAncestors(
+		ctx context.Context,
+		db Querier,
+		prId string,
+	) ([]entity.PullRequest, error)
 
 	AddPullRequest(ctx context.Context, db Querier, ent *entity.PullRequest) error
+	AddCompletedReviews(ctx context.Context, db Querier, prId string) error
 	UpdatePullRequestStatus(ctx context.Context, db Querier, prId string, newStatus string) error
+	AddPullRequestPaths(ctx context.Context, db Querier, prId string, paths []string) error
+	AddPullRequestLabels(ctx context.Context, db Querier, prId string, labels []string) error
+	SetPullRequestLabels(ctx context.Context, db Querier, prId string, labels []string) error
+	UpdatePullRequest(
+		ctx context.Context,
+		db Querier,
+		prId string,
+		update *entity.PullRequestUpdate,
+	) error
+	AddPullRequestEvent(ctx context.Context, db Querier, ent *entity.PullRequestEvent) error
 
 	AddReviewerToPullRequest(ctx context.Context, db Querier, prId string, reviewerId string) error
 	RemoveReviewerFromPullRequest(
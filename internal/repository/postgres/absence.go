@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+// availableCondition matches users u that are active and not absent at
+// the moment of the query.
+var availableCondition = fmt.Sprintf(`
+	u.is_active = true AND NOT EXISTS (
+		SELECT 1 FROM users_absences a
+		WHERE a.user_id = u.id AND (
+			(a.kind = '%s' AND now() >= a.starts_at AND now() < a.ends_at) OR
+			(a.kind = '%s' AND a.weekday = EXTRACT(ISODOW FROM now() AT TIME ZONE 'UTC'))
+		)
+	)
+`, entity.AbsenceVacation, entity.AbsenceDayOff)
+
+type PostgresAbsenceRepository struct {
+	logger *slog.Logger
+}
+
+func NewPostgresAbsenceRepository(
+	baseLogger *slog.Logger,
+) repository.BaseAbsenceRepository {
+	logger := baseLogger.With("module", "absencerepo")
+	return &PostgresAbsenceRepository{
+		logger: logger,
+	}
+}
+
+func (p *PostgresAbsenceRepository) GetAbsencesByUserId(
+	ctx context.Context,
+	db repository.Querier,
+	userId string,
+) ([]entity.Absence, error) {
+	query := `
+		SELECT id, user_id, kind, starts_at, ends_at, weekday, reason
+		FROM users_absences
+		WHERE user_id = $1
+		ORDER BY id
+	`
+	var absences []entity.Absence
+
+	rows, err := db.Query(ctx, query, userId)
+	if err != nil {
+		p.logger.Debug("failed to GetAbsencesByUserId", "userId", userId, "err", err)
+		return nil, errs.ErrInternal("failed to GetAbsencesByUserId", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a entity.Absence
+		err := rows.Scan(
+			&a.Id,
+			&a.UserId,
+			&a.Kind,
+			&a.StartsAt,
+			&a.EndsAt,
+			&a.Weekday,
+			&a.Reason,
+		)
+		if err != nil {
+			p.logger.Debug(
+				"failed to GetAbsencesByUserId: scan error",
+				"userId",
+				userId,
+				"err",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetAbsencesByUserId: scan error", err)
+		}
+		absences = append(absences, a)
+	}
+	return absences, nil
+}
+
+func (p *PostgresAbsenceRepository) AddAbsence(
+	ctx context.Context,
+	db repository.Querier,
+	ent *entity.Absence,
+) error {
+	query := `
+		INSERT INTO users_absences (user_id, kind, starts_at, ends_at, weekday, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	err := db.QueryRow(
+		ctx,
+		query,
+		ent.UserId,
+		ent.Kind,
+		ent.StartsAt,
+		ent.EndsAt,
+		ent.Weekday,
+		ent.Reason,
+	).Scan(&ent.Id)
+	if err != nil {
+		p.logger.Debug("failed to AddAbsence", "absence", ent, "err", err)
+		return errs.ErrInternal("failed to AddAbsence", err)
+	}
+	return nil
+}
+
+func (p *PostgresAbsenceRepository) RemoveAbsence(
+	ctx context.Context,
+	db repository.Querier,
+	userId string,
+	id int64,
+) error {
+	query := `
+		DELETE FROM users_absences
+		WHERE user_id = $1 AND id = $2
+	`
+	ct, err := db.Exec(ctx, query, userId, id)
+	if err != nil {
+		p.logger.Debug("failed to RemoveAbsence", "userId", userId, "id", id, "err", err)
+		return errs.ErrInternal("failed to RemoveAbsence", err)
+	}
+	if ct.RowsAffected() == 0 {
+		p.logger.Debug("failed to RemoveAbsence: not found", "userId", userId, "id", id)
+		return errs.ErrNotFound("absence", "id", id)
+	}
+	return nil
+}
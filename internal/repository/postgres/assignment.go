@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PostgresAssignmentRepository struct {
+	logger *slog.Logger
+}
+
+func NewPostgresAssignmentRepository(
+	baseLogger *slog.Logger,
+) repository.BaseAssignmentRepository {
+	logger := baseLogger.With("module", "assignmentrepo")
+	return &PostgresAssignmentRepository{
+		logger: logger,
+	}
+}
+
+func (p *PostgresAssignmentRepository) GetAssignmentById(
+	ctx context.Context,
+	db repository.Querier,
+	id int64,
+) (*entity.Assignment, error) {
+	query := `
+		SELECT id, pr_id, kind, seed, steps, reviewers, created_at
+		FROM pull_requests_assignments
+		WHERE id = $1
+	`
+	var a entity.Assignment
+	var seed int64
+
+	err := db.QueryRow(ctx, query, id).Scan(
+		&a.Id,
+		&a.PullRequestId,
+		&a.Kind,
+		&seed,
+		&a.Steps,
+		&a.Reviewers,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.logger.Debug("failed to GetAssignmentById: not found", "id", id)
+			return nil, errs.ErrNotFound("assignment", "id", id)
+		}
+		p.logger.Debug("failed to GetAssignmentById", "id", id, "err", err)
+		return nil, errs.ErrInternal("failed to GetAssignmentById", err)
+	}
+	a.Seed = uint64(seed)
+	return &a, nil
+}
+
+func (p *PostgresAssignmentRepository) GetAssignmentsByPrId(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]entity.Assignment, error) {
+	query := `
+		SELECT id, pr_id, kind, seed, steps, reviewers, created_at
+		FROM pull_requests_assignments
+		WHERE pr_id = $1
+		ORDER BY id
+	`
+	var assignments []entity.Assignment
+
+	rows, err := db.Query(ctx, query, prId)
+	if err != nil {
+		p.logger.Debug("failed to GetAssignmentsByPrId", "prId", prId, "err", err)
+		return nil, errs.ErrInternal("failed to GetAssignmentsByPrId", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a entity.Assignment
+		var seed int64
+		err := rows.Scan(
+			&a.Id,
+			&a.PullRequestId,
+			&a.Kind,
+			&seed,
+			&a.Steps,
+			&a.Reviewers,
+			&a.CreatedAt,
+		)
+		if err != nil {
+			p.logger.Debug("failed to GetAssignmentsByPrId: scan error", "prId", prId, "err", err)
+			return nil, errs.ErrInternal("failed to GetAssignmentsByPrId: scan error", err)
+		}
+		a.Seed = uint64(seed)
+		assignments = append(assignments, a)
+	}
+	return assignments, nil
+}
+
+func (p *PostgresAssignmentRepository) AddAssignment(
+	ctx context.Context,
+	db repository.Querier,
+	ent *entity.Assignment,
+) error {
+	query := `
+		INSERT INTO pull_requests_assignments (pr_id, kind, seed, steps, reviewers)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	reviewers := ent.Reviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	steps := ent.Steps
+	if steps == nil {
+		steps = []entity.AssignmentStep{}
+	}
+
+	err := db.QueryRow(
+		ctx,
+		query,
+		ent.PullRequestId,
+		ent.Kind,
+		int64(ent.Seed),
+		steps,
+		reviewers,
+	).Scan(&ent.Id, &ent.CreatedAt)
+	if err != nil {
+		p.logger.Debug("failed to AddAssignment", "assignment", ent, "err", err)
+		return errs.ErrInternal("failed to AddAssignment", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PostgresCommentRepository struct {
+	logger *slog.Logger
+}
+
+func NewPostgresCommentRepository(
+	baseLogger *slog.Logger,
+) repository.BaseCommentRepository {
+	logger := baseLogger.With("module", "commentrepo")
+	return &PostgresCommentRepository{
+		logger: logger,
+	}
+}
+
+func (p *PostgresCommentRepository) GetCommentById(
+	ctx context.Context,
+	db repository.Querier,
+	commentId int64,
+) (*entity.Comment, error) {
+	query := `
+		SELECT id, pr_id, parent_id, author_id, body, resolved_by, resolved_at, created_at
+		FROM pull_requests_comments
+		WHERE id = $1
+	`
+	var c entity.Comment
+	err := db.QueryRow(ctx, query, commentId).Scan(
+		&c.Id,
+		&c.PullRequestId,
+		&c.ParentId,
+		&c.AuthorId,
+		&c.Body,
+		&c.ResolvedBy,
+		&c.ResolvedAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.logger.Debug("failed to GetCommentById: not found", "commentId", commentId)
+			return nil, errs.ErrNotFound("comment", "id", commentId)
+		}
+		p.logger.Debug("failed to GetCommentById", "commentId", commentId, "err", err)
+		return nil, errs.ErrInternal("failed to GetCommentById", err)
+	}
+	return &c, nil
+}
+
+// GetCommentsByPrId returns all comments of the PR in the order they were
+// left.
+func (p *PostgresCommentRepository) GetCommentsByPrId(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]entity.Comment, error) {
+	query := `
+		SELECT id, pr_id, parent_id, author_id, body, resolved_by, resolved_at, created_at
+		FROM pull_requests_comments
+		WHERE pr_id = $1
+		ORDER BY created_at, id
+	`
+	var comments []entity.Comment
+
+	rows, err := db.Query(ctx, query, prId)
+	if err != nil {
+		p.logger.Debug("failed to GetCommentsByPrId", "prId", prId, "err", err)
+		return nil, errs.ErrInternal("failed to GetCommentsByPrId", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c entity.Comment
+		err := rows.Scan(
+			&c.Id,
+			&c.PullRequestId,
+			&c.ParentId,
+			&c.AuthorId,
+			&c.Body,
+			&c.ResolvedBy,
+			&c.ResolvedAt,
+			&c.CreatedAt,
+		)
+		if err != nil {
+			p.logger.Debug("failed to GetCommentsByPrId: scan error", "prId", prId, "err", err)
+			return nil, errs.ErrInternal("failed to GetCommentsByPrId: scan error", err)
+		}
+		comments = append(comments, c)
+	}
+	return comments, nil
+}
+
+func (p *PostgresCommentRepository) CountUnresolvedThreads(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM pull_requests_comments
+		WHERE pr_id = $1 AND parent_id IS NULL AND resolved_at IS NULL
+	`
+	var count int
+	err := db.QueryRow(ctx, query, prId).Scan(&count)
+	if err != nil {
+		p.logger.Debug("failed to CountUnresolvedThreads", "prId", prId, "err", err)
+		return 0, errs.ErrInternal("failed to CountUnresolvedThreads", err)
+	}
+	return count, nil
+}
+
+func (p *PostgresCommentRepository) CountUnresolvedThreadsByPrIds(
+	ctx context.Context,
+	db repository.Querier,
+	prIds []string,
+) (map[string]int, error) {
+	query := `
+		SELECT pr_id, COUNT(*) FROM pull_requests_comments
+		WHERE pr_id = ANY($1) AND parent_id IS NULL AND resolved_at IS NULL
+		GROUP BY pr_id
+	`
+	counts := make(map[string]int)
+	rows, err := db.Query(ctx, query, prIds)
+	if err != nil {
+		p.logger.Debug("failed to CountUnresolvedThreadsByPrIds", "prIds", prIds, "err", err)
+		return nil, errs.ErrInternal("failed to CountUnresolvedThreadsByPrIds", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prId string
+		var count int
+		if err := rows.Scan(&prId, &count); err != nil {
+			p.logger.Debug(
+				"failed to CountUnresolvedThreadsByPrIds: scan error",
+				"prIds",
+				prIds,
+				"err",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to CountUnresolvedThreadsByPrIds: scan error", err)
+		}
+		counts[prId] = count
+	}
+	return counts, nil
+}
+
+func (p *PostgresCommentRepository) AddComment(
+	ctx context.Context,
+	db repository.Querier,
+	comment *entity.Comment,
+) error {
+	query := `
+		INSERT INTO pull_requests_comments (pr_id, parent_id, author_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	err := db.QueryRow(
+		ctx,
+		query,
+		comment.PullRequestId,
+		comment.ParentId,
+		comment.AuthorId,
+		comment.Body,
+	).Scan(&comment.Id, &comment.CreatedAt)
+	if err != nil {
+		p.logger.Debug("failed to AddComment", "comment", comment, "err", err)
+		return errs.ErrInternal("failed to AddComment", err)
+	}
+	return nil
+}
+
+// ResolveComment marks the thread started by commentId as resolved by
+// userId. Resolving a resolved thread keeps the first resolution.
+func (p *PostgresCommentRepository) ResolveComment(
+	ctx context.Context,
+	db repository.Querier,
+	commentId int64,
+	userId string,
+) error {
+	query := `
+		UPDATE pull_requests_comments
+		SET resolved_by = $1, resolved_at = now()
+		WHERE id = $2 AND parent_id IS NULL AND resolved_at IS NULL
+	`
+	_, err := db.Exec(ctx, query, userId, commentId)
+	if err != nil {
+		p.logger.Debug(
+			"failed to ResolveComment",
+			"commentId",
+			commentId,
+			"userId",
+			userId,
+			"err",
+			err,
+		)
+		return errs.ErrInternal("failed to ResolveComment", err)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
@@ -14,6 +15,14 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// recentReviewsQuery counts reviews user u completed within the review
+// window of team t.
+const recentReviewsQuery = `
+	SELECT COUNT(*) FROM completed_reviews cr
+	WHERE cr.user_id = u.id
+	AND cr.completed_at > now() - make_interval(days => t.review_window_days)
+`
+
 type PostgresPullRequestRepository struct {
 	logger *slog.Logger
 }
@@ -33,7 +42,9 @@ func (p *PostgresPullRequestRepository) GetPullRequestsByReviewerId(
 	reviewerId string,
 ) ([]entity.PullRequest, error) {
 	query := `
-		SELECT id, name, author_id, status, updated_at FROM pull_requests pr
+		SELECT id, name, description, author_id, status, parent_id,
+			repository_name, number, created_at, merged_at, closed_at, updated_at
+		FROM pull_requests pr
 		JOIN pull_requests_users pr_u ON pr.id = pr_u.pr_id
         WHERE pr_u.user_id = $1
 	`
@@ -57,8 +68,15 @@ func (p *PostgresPullRequestRepository) GetPullRequestsByReviewerId(
 		err := rows.Scan(
 			&pr.Id,
 			&pr.PullRequestName,
+			&pr.Description,
 			&pr.AuthorId,
 			&pr.Status,
+			&pr.ParentId,
+			&pr.RepositoryName,
+			&pr.Number,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
 			&pr.UpdatedAt,
 		)
 		if err != nil {
@@ -82,15 +100,24 @@ func (p *PostgresPullRequestRepository) GetPullRequestById(
 	prId string,
 ) (*entity.PullRequest, error) {
 	query := `
-		SELECT id, name, author_id, status, updated_at FROM pull_requests 
+		SELECT id, name, description, author_id, status, parent_id,
+			repository_name, number, created_at, merged_at, closed_at, updated_at
+		FROM pull_requests
         WHERE id = $1
 	`
 	var pr entity.PullRequest
 	err := db.QueryRow(ctx, query, prId).Scan(
 		&pr.Id,
 		&pr.PullRequestName,
+		&pr.Description,
 		&pr.AuthorId,
 		&pr.Status,
+		&pr.ParentId,
+		&pr.RepositoryName,
+		&pr.Number,
+		&pr.CreatedAt,
+		&pr.MergedAt,
+		&pr.ClosedAt,
 		&pr.UpdatedAt,
 	)
 	if err != nil {
@@ -104,6 +131,281 @@ func (p *PostgresPullRequestRepository) GetPullRequestById(
 	return &pr, nil
 }
 
+func (p *PostgresPullRequestRepository) GetPullRequestByIdForUpdate(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) (*entity.PullRequest, error) {
+	query := `
+		SELECT id, name, description, author_id, status, parent_id,
+			repository_name, number, created_at, merged_at, closed_at, updated_at
+		FROM pull_requests
+        WHERE id = $1
+		FOR UPDATE
+	`
+	var pr entity.PullRequest
+	err := db.QueryRow(ctx, query, prId).Scan(
+		&pr.Id,
+		&pr.PullRequestName,
+		&pr.Description,
+		&pr.AuthorId,
+		&pr.Status,
+		&pr.ParentId,
+		&pr.RepositoryName,
+		&pr.Number,
+		&pr.CreatedAt,
+		&pr.MergedAt,
+		&pr.ClosedAt,
+		&pr.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.logger.Debug("failed to GetPullRequestByIdForUpdate: not found", "prId", prId)
+			return nil, errs.ErrNotFound("pull request", "id", prId)
+		}
+		p.logger.Debug("failed to GetPullRequestByIdForUpdate", "prId", prId, "err", err)
+		return nil, errs.ErrInternal("failed to GetPullRequestByIdForUpdate", err)
+	}
+	return &pr, nil
+}
+
+func (p *PostgresPullRequestRepository) GetPullRequestByNumber(
+	ctx context.Context,
+	db repository.Querier,
+	repositoryName string,
+	number int,
+) (*entity.PullRequest, error) {
+	query := `
+		SELECT id, name, description, author_id, status, parent_id,
+			repository_name, number, created_at, merged_at, closed_at, updated_at
+		FROM pull_requests
+		WHERE repository_name = $1 AND number = $2
+	`
+	var pr entity.PullRequest
+	err := db.QueryRow(ctx, query, repositoryName, number).Scan(
+		&pr.Id,
+		&pr.PullRequestName,
+		&pr.Description,
+		&pr.AuthorId,
+		&pr.Status,
+		&pr.ParentId,
+		&pr.RepositoryName,
+		&pr.Number,
+		&pr.CreatedAt,
+		&pr.MergedAt,
+		&pr.ClosedAt,
+		&pr.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.logger.Debug(
+				"failed to GetPullRequestByNumber: not found",
+				"repositoryName",
+				repositoryName,
+				"number",
+				number,
+			)
+			return nil, errs.ErrNotFound(
+				"pull request",
+				"repository and number",
+				fmt.Sprintf("%s, %d", repositoryName, number),
+			)
+		}
+		p.logger.Debug(
+			"failed to GetPullRequestByNumber",
+			"repositoryName",
+			repositoryName,
+			"number",
+			number,
+			"err",
+			err,
+		)
+		return nil, errs.ErrInternal("failed to GetPullRequestByNumber", err)
+	}
+	return &pr, nil
+}
+
+// ListPullRequests returns up to filter.Limit PRs matching filter, ordered
+// by filter.SortBy with the id as a tie breaker.
+func (p *PostgresPullRequestRepository) ListPullRequests(
+	ctx context.Context,
+	db repository.Querier,
+	filter *entity.PullRequestFilter,
+) ([]entity.PullRequest, error) {
+	sortCol := "pr.created_at"
+	if filter.SortBy == entity.SortName {
+		sortCol = "pr.name"
+	}
+	order, cmp := "ASC", ">"
+	if filter.Descending {
+		order, cmp = "DESC", "<"
+	}
+
+	conds := make([]string, 0)
+	args := make([]interface{}, 0)
+	addCond := func(cond string, value interface{}) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.Status != nil {
+		addCond("pr.status = $%d", *filter.Status)
+	}
+	if filter.AuthorId != nil {
+		addCond("pr.author_id = $%d", *filter.AuthorId)
+	}
+	if filter.TeamName != nil {
+		addCond("pr.author_id IN (SELECT id FROM users WHERE team_name = $%d)", *filter.TeamName)
+	}
+	if filter.RepositoryName != nil {
+		addCond("pr.repository_name = $%d", *filter.RepositoryName)
+	}
+	if filter.ReviewerId != nil {
+		addCond(
+			"EXISTS (SELECT 1 FROM pull_requests_users WHERE pr_id = pr.id AND user_id = $%d)",
+			*filter.ReviewerId,
+		)
+	}
+	if filter.Label != nil {
+		addCond(
+			"EXISTS (SELECT 1 FROM pull_requests_labels WHERE pr_id = pr.id AND label = $%d)",
+			*filter.Label,
+		)
+	}
+	if filter.CreatedFrom != nil {
+		addCond("pr.created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCond("pr.created_at < $%d", *filter.CreatedTo)
+	}
+	if filter.MergedFrom != nil {
+		addCond("pr.merged_at >= $%d", *filter.MergedFrom)
+	}
+	if filter.MergedTo != nil {
+		addCond("pr.merged_at < $%d", *filter.MergedTo)
+	}
+	if filter.After != nil {
+		var after interface{} = filter.After.CreatedAt
+		if filter.SortBy == entity.SortName {
+			after = filter.After.PullRequestName
+		}
+		args = append(args, after, filter.After.Id)
+		conds = append(
+			conds,
+			fmt.Sprintf("(%s, pr.id) %s ($%d, $%d)", sortCol, cmp, len(args)-1, len(args)),
+		)
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT pr.id, pr.name, pr.description, pr.author_id, pr.status, pr.parent_id,
+			pr.repository_name, pr.number, pr.created_at, pr.merged_at, pr.closed_at, pr.updated_at
+		FROM pull_requests pr
+		%s
+		ORDER BY %s %s, pr.id %s
+		LIMIT $%d
+	`, where, sortCol, order, order, len(args))
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Debug("failed to ListPullRequests", "query", query, "args", args, "err", err)
+		return nil, errs.ErrInternal("failed to ListPullRequests", err)
+	}
+	defer rows.Close()
+
+	prs := make([]entity.PullRequest, 0)
+	for rows.Next() {
+		var pr entity.PullRequest
+		err := rows.Scan(
+			&pr.Id,
+			&pr.PullRequestName,
+			&pr.Description,
+			&pr.AuthorId,
+			&pr.Status,
+			&pr.ParentId,
+			&pr.RepositoryName,
+			&pr.Number,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
+			&pr.UpdatedAt,
+		)
+		if err != nil {
+			p.logger.Debug("failed to ListPullRequests: scan error", "err", err)
+			return nil, errs.ErrInternal("failed to ListPullRequests: scan error", err)
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// GetPullRequestAncestors returns the PRs prId is stacked on, starting
+// with its parent. A cycle in the chain ends it at the first repeated PR.
+func (p *PostgresPullRequestRepository) GetPullRequestAncestors(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]entity.PullRequest, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT parent.*, 1 AS depth, ARRAY[child.id, parent.id] AS path
+			FROM pull_requests child
+			JOIN pull_requests parent ON parent.id = child.parent_id
+			WHERE child.id = $1
+			UNION ALL
+			SELECT pr.*, c.depth + 1, c.path || pr.id
+			FROM pull_requests pr
+			JOIN chain c ON pr.id = c.parent_id
+			WHERE NOT pr.id = ANY(c.path)
+		)
+		SELECT id, name, description, author_id, status, parent_id,
+			repository_name, number, created_at, merged_at, closed_at, updated_at
+		FROM chain
+		ORDER BY depth
+	`
+	var prs []entity.PullRequest
+
+	rows, err := db.Query(ctx, query, prId)
+	if err != nil {
+		p.logger.Debug("failed to GetPullRequestAncestors", "prId", prId, "err", err)
+		return nil, errs.ErrInternal("failed to GetPullRequestAncestors", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pr entity.PullRequest
+		err := rows.Scan(
+			&pr.Id,
+			&pr.PullRequestName,
+			&pr.Description,
+			&pr.AuthorId,
+			&pr.Status,
+			&pr.ParentId,
+			&pr.RepositoryName,
+			&pr.Number,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
+			&pr.UpdatedAt,
+		)
+		if err != nil {
+			p.logger.Debug(
+				"failed to GetPullRequestAncestors: scan error",
+				"prId",
+				prId,
+				"err",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetPullRequestAncestors: scan error", err)
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
 func (p *PostgresPullRequestRepository) GetOpenPullRequestsByReviewers(ctx context.Context, db repository.Querier) ([]entity.UserStats, error) {
 	query := `
 		SELECT u.id, u.username, COUNT(*) FROM users u
@@ -135,16 +437,239 @@ func (p *PostgresPullRequestRepository) GetOpenPullRequestsByReviewers(ctx conte
 	return userStats, nil
 }
 
+func (p *PostgresPullRequestRepository) GetOpenPullRequestsByTeamMembers(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+) ([]entity.UserStats, error) {
+	query := `
+		SELECT u.id, u.username, COUNT(pr.id), COALESCE(u.max_open_reviews, t.max_open_reviews),
+		(` + recentReviewsQuery + `)
+		FROM users u
+		JOIN teams t ON u.team_name = t.name
+		LEFT JOIN pull_requests_users pr_u ON u.id = pr_u.user_id
+		LEFT JOIN pull_requests pr ON pr_u.pr_id = pr.id AND pr.status = $2
+		WHERE u.team_name = $1 AND ` + availableCondition + `
+		GROUP BY u.id, t.name
+	`
+	var userStats []entity.UserStats
+	rows, err := db.Query(ctx, query, teamName, entity.StatusOpen)
+	if err != nil {
+		p.logger.Debug("failed to GetOpenPullRequestsByTeamMembers", "teamName", teamName, "err", err)
+		return nil, errs.ErrInternal("failed to GetOpenPullRequestsByTeamMembers", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stats entity.UserStats
+		err := rows.Scan(
+			&stats.Id,
+			&stats.Username,
+			&stats.OpenPullRequestsCount,
+			&stats.MaxOpenReviews,
+			&stats.RecentReviewsCount,
+		)
+		if err != nil {
+			p.logger.Debug(
+				"failed to GetOpenPullRequestsByTeamMembers: scan error",
+				"teamName",
+				teamName,
+				"err",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetOpenPullRequestsByTeamMembers: scan error", err)
+		}
+		userStats = append(userStats, stats)
+	}
+	return userStats, nil
+}
+
+func (p *PostgresPullRequestRepository) GetOpenPullRequestsByUserIds(
+	ctx context.Context,
+	db repository.Querier,
+	userIds []string,
+) ([]entity.UserStats, error) {
+	query := `
+		SELECT u.id, u.username, COUNT(pr.id), COALESCE(u.max_open_reviews, t.max_open_reviews),
+		(` + recentReviewsQuery + `)
+		FROM users u
+		JOIN teams t ON u.team_name = t.name
+		LEFT JOIN pull_requests_users pr_u ON u.id = pr_u.user_id
+		LEFT JOIN pull_requests pr ON pr_u.pr_id = pr.id AND pr.status = $2
+		WHERE u.id = ANY($1) AND ` + availableCondition + `
+		GROUP BY u.id, t.name
+	`
+	var userStats []entity.UserStats
+	rows, err := db.Query(ctx, query, userIds, entity.StatusOpen)
+	if err != nil {
+		p.logger.Debug("failed to GetOpenPullRequestsByUserIds", "userIds", userIds, "err", err)
+		return nil, errs.ErrInternal("failed to GetOpenPullRequestsByUserIds", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stats entity.UserStats
+		err := rows.Scan(
+			&stats.Id,
+			&stats.Username,
+			&stats.OpenPullRequestsCount,
+			&stats.MaxOpenReviews,
+			&stats.RecentReviewsCount,
+		)
+		if err != nil {
+			p.logger.Debug(
+				"failed to GetOpenPullRequestsByUserIds: scan error",
+				"userIds",
+				userIds,
+				"err",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetOpenPullRequestsByUserIds: scan error", err)
+		}
+		userStats = append(userStats, stats)
+	}
+	return userStats, nil
+}
+
+func (p *PostgresPullRequestRepository) GetPullRequestPaths(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]string, error) {
+	query := `
+		SELECT path FROM pull_requests_paths
+		WHERE pr_id = $1
+		ORDER BY path
+	`
+	var paths []string
+	rows, err := db.Query(ctx, query, prId)
+	if err != nil {
+		p.logger.Debug("failed to GetPullRequestPaths", "prId", prId, "err", err)
+		return nil, errs.ErrInternal("failed to GetPullRequestPaths", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			p.logger.Debug("failed to GetPullRequestPaths: scan error", "prId", prId, "err", err)
+			return nil, errs.ErrInternal("failed to GetPullRequestPaths: scan error", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func (p *PostgresPullRequestRepository) GetPullRequestLabels(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]string, error) {
+	query := `
+		SELECT label FROM pull_requests_labels
+		WHERE pr_id = $1
+		ORDER BY label
+	`
+	var labels []string
+	rows, err := db.Query(ctx, query, prId)
+	if err != nil {
+		p.logger.Debug("failed to GetPullRequestLabels", "prId", prId, "err", err)
+		return nil, errs.ErrInternal("failed to GetPullRequestLabels", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			p.logger.Debug("failed to GetPullRequestLabels: scan error", "prId", prId, "err", err)
+			return nil, errs.ErrInternal("failed to GetPullRequestLabels: scan error", err)
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+func (p *PostgresPullRequestRepository) GetLabelsByPrIds(
+	ctx context.Context,
+	db repository.Querier,
+	prIds []string,
+) ([]entity.PullRequestLabel, error) {
+	query := `
+		SELECT pr_id, label FROM pull_requests_labels
+		WHERE pr_id = ANY($1)
+		ORDER BY pr_id, label
+	`
+	var labels []entity.PullRequestLabel
+	rows, err := db.Query(ctx, query, prIds)
+	if err != nil {
+		p.logger.Debug("failed to GetLabelsByPrIds", "prIds", prIds, "err", err)
+		return nil, errs.ErrInternal("failed to GetLabelsByPrIds", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var label entity.PullRequestLabel
+		if err := rows.Scan(&label.PullRequestId, &label.Label); err != nil {
+			p.logger.Debug("failed to GetLabelsByPrIds: scan error", "prIds", prIds, "err", err)
+			return nil, errs.ErrInternal("failed to GetLabelsByPrIds: scan error", err)
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+func (p *PostgresPullRequestRepository) GetReviewersByPrIds(
+	ctx context.Context,
+	db repository.Querier,
+	prIds []string,
+) ([]entity.PullRequestReviewer, error) {
+	query := `
+		SELECT pr_id, user_id FROM pull_requests_users
+		WHERE pr_id = ANY($1)
+		ORDER BY pr_id, assigned_at, user_id
+	`
+	var reviewers []entity.PullRequestReviewer
+	rows, err := db.Query(ctx, query, prIds)
+	if err != nil {
+		p.logger.Debug("failed to GetReviewersByPrIds", "prIds", prIds, "err", err)
+		return nil, errs.ErrInternal("failed to GetReviewersByPrIds", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewer entity.PullRequestReviewer
+		if err := rows.Scan(&reviewer.PullRequestId, &reviewer.UserId); err != nil {
+			p.logger.Debug("failed to GetReviewersByPrIds: scan error", "prIds", prIds, "err", err)
+			return nil, errs.ErrInternal("failed to GetReviewersByPrIds: scan error", err)
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+	return reviewers, nil
+}
+
 func (p *PostgresPullRequestRepository) AddPullRequest(
 	ctx context.Context,
 	db repository.Querier,
 	ent *entity.PullRequest,
 ) error {
 	query := `
-		INSERT INTO pull_requests (id, name, author_id, status)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO pull_requests
+		(id, name, description, author_id, status, parent_id, repository_name, number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
 	`
-	_, err := db.Exec(ctx, query, ent.Id, ent.PullRequestName, ent.AuthorId, ent.Status)
+	err := db.QueryRow(
+		ctx,
+		query,
+		ent.Id,
+		ent.PullRequestName,
+		ent.Description,
+		ent.AuthorId,
+		ent.Status,
+		ent.ParentId,
+		ent.RepositoryName,
+		ent.Number,
+	).Scan(&ent.CreatedAt)
 	if err != nil {
 		p.logger.Debug("failed to AddPullRequest", "err", err)
 		return errs.ErrInternal("failed to AddPullRequest", err)
@@ -152,6 +677,24 @@ func (p *PostgresPullRequestRepository) AddPullRequest(
 	return nil
 }
 
+func (p *PostgresPullRequestRepository) AddCompletedReviews(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) error {
+	query := `
+		INSERT INTO completed_reviews (pr_id, user_id, assigned_at)
+		SELECT pr_id, user_id, assigned_at FROM pull_requests_users
+		WHERE pr_id = $1
+	`
+	_, err := db.Exec(ctx, query, prId)
+	if err != nil {
+		p.logger.Debug("failed to AddCompletedReviews", "prId", prId, "err", err)
+		return errs.ErrInternal("failed to AddCompletedReviews", err)
+	}
+	return nil
+}
+
 func (p *PostgresPullRequestRepository) UpdatePullRequestStatus(
 	ctx context.Context,
 	db repository.Querier,
@@ -160,11 +703,21 @@ func (p *PostgresPullRequestRepository) UpdatePullRequestStatus(
 ) error {
 	query := `
 		UPDATE pull_requests
-		SET status = $1, updated_at = $2
+		SET status = $1, updated_at = $2,
+			merged_at = CASE WHEN $1 = $4 THEN $2 ELSE merged_at END,
+			closed_at = CASE WHEN $1 = $5 THEN $2 END
 		WHERE id = $3
 	`
 
-	ct, err := db.Exec(ctx, query, newStatus, time.Now(), prId)
+	ct, err := db.Exec(
+		ctx,
+		query,
+		newStatus,
+		time.Now(),
+		prId,
+		entity.StatusMerged,
+		entity.StatusClosed,
+	)
 	if err != nil {
 		p.logger.Debug(
 			"failed to UpdatePullRequestStatus",
@@ -190,6 +743,123 @@ func (p *PostgresPullRequestRepository) UpdatePullRequestStatus(
 	return nil
 }
 
+func (p *PostgresPullRequestRepository) AddPullRequestPaths(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	paths []string,
+) error {
+	query := `
+		INSERT INTO pull_requests_paths (pr_id, path)
+		SELECT $1, unnest($2::varchar[])
+		ON CONFLICT DO NOTHING
+	`
+	_, err := db.Exec(ctx, query, prId, paths)
+	if err != nil {
+		p.logger.Debug("failed to AddPullRequestPaths", "prId", prId, "paths", paths, "err", err)
+		return errs.ErrInternal("failed to AddPullRequestPaths", err)
+	}
+	return nil
+}
+
+func (p *PostgresPullRequestRepository) AddPullRequestLabels(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	labels []string,
+) error {
+	query := `
+		INSERT INTO pull_requests_labels (pr_id, label)
+		SELECT $1, unnest($2::varchar[])
+		ON CONFLICT DO NOTHING
+	`
+	_, err := db.Exec(ctx, query, prId, labels)
+	if err != nil {
+		p.logger.Debug(
+			"failed to AddPullRequestLabels",
+			"prId",
+			prId,
+			"labels",
+			labels,
+			"err",
+			err,
+		)
+		return errs.ErrInternal("failed to AddPullRequestLabels", err)
+	}
+	return nil
+}
+
+// SetPullRequestLabels replaces all labels of the PR with labels.
+func (p *PostgresPullRequestRepository) SetPullRequestLabels(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	labels []string,
+) error {
+	_, err := db.Exec(ctx, "DELETE FROM pull_requests_labels WHERE pr_id = $1", prId)
+	if err != nil {
+		p.logger.Debug("failed to SetPullRequestLabels: delete error", "prId", prId, "err", err)
+		return errs.ErrInternal("failed to SetPullRequestLabels: delete error", err)
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return p.AddPullRequestLabels(ctx, db, prId, labels)
+}
+
+func (p *PostgresPullRequestRepository) UpdatePullRequest(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	update *entity.PullRequestUpdate,
+) error {
+	if update.PullRequestName == nil && update.Description == nil && update.ParentId == nil {
+		return errs.ErrBadFilter("PullRequestName or Description or ParentId is required")
+	}
+
+	query := `
+		UPDATE pull_requests
+		SET 
+	`
+
+	currUpdate := 1
+	values := make([]string, 0)
+	args := make([]interface{}, 0)
+	if update.PullRequestName != nil {
+		values = append(values, fmt.Sprintf("name = $%d", currUpdate))
+		args = append(args, *update.PullRequestName)
+		currUpdate++
+	}
+	if update.Description != nil {
+		values = append(values, fmt.Sprintf("description = $%d", currUpdate))
+		args = append(args, *update.Description)
+		currUpdate++
+	}
+	if update.ParentId != nil {
+		values = append(values, fmt.Sprintf("parent_id = NULLIF($%d, '')", currUpdate))
+		args = append(args, *update.ParentId)
+		currUpdate++
+	}
+	query = fmt.Sprintf(
+		"%s %s %s",
+		query,
+		strings.Join(values, ", "),
+		fmt.Sprintf("WHERE id = $%d", currUpdate),
+	)
+	args = append(args, prId)
+
+	ct, err := db.Exec(ctx, query, args...)
+	if err != nil {
+		p.logger.Debug("failed to UpdatePullRequest", "query", query, "args", args, "error", err)
+		return errs.ErrInternal("failed to UpdatePullRequest", err)
+	}
+	if ct.RowsAffected() == 0 {
+		p.logger.Debug("failed to UpdatePullRequest: not found", "prId", prId)
+		return errs.ErrNotFound("pull request", "id", prId)
+	}
+	return nil
+}
+
 func (p *PostgresPullRequestRepository) AddReviewerToPullRequest(
 	ctx context.Context,
 	db repository.Querier,
@@ -255,3 +925,74 @@ func (p *PostgresPullRequestRepository) RemoveReviewerFromPullRequest(
 	}
 	return nil
 }
+
+func (p *PostgresPullRequestRepository) GetPullRequestEvents(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]entity.PullRequestEvent, error) {
+	query := `
+		SELECT id, pr_id, kind, actor_id, user_id, from_status, to_status, fields, created_at
+		FROM pull_request_events
+		WHERE pr_id = $1
+		ORDER BY id
+	`
+	var events []entity.PullRequestEvent
+
+	rows, err := db.Query(ctx, query, prId)
+	if err != nil {
+		p.logger.Debug("failed to GetPullRequestEvents", "prId", prId, "err", err)
+		return nil, errs.ErrInternal("failed to GetPullRequestEvents", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e entity.PullRequestEvent
+		err := rows.Scan(
+			&e.Id,
+			&e.PullRequestId,
+			&e.Kind,
+			&e.ActorId,
+			&e.UserId,
+			&e.FromStatus,
+			&e.ToStatus,
+			&e.Fields,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			p.logger.Debug("failed to GetPullRequestEvents: scan error", "prId", prId, "err", err)
+			return nil, errs.ErrInternal("failed to GetPullRequestEvents: scan error", err)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func (p *PostgresPullRequestRepository) AddPullRequestEvent(
+	ctx context.Context,
+	db repository.Querier,
+	ent *entity.PullRequestEvent,
+) error {
+	query := `
+		INSERT INTO pull_request_events
+		(pr_id, kind, actor_id, user_id, from_status, to_status, fields)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err := db.QueryRow(
+		ctx,
+		query,
+		ent.PullRequestId,
+		ent.Kind,
+		ent.ActorId,
+		ent.UserId,
+		ent.FromStatus,
+		ent.ToStatus,
+		ent.Fields,
+	).Scan(&ent.Id, &ent.CreatedAt)
+	if err != nil {
+		p.logger.Debug("failed to AddPullRequestEvent", "event", ent, "err", err)
+		return errs.ErrInternal("failed to AddPullRequestEvent", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+type PostgresReviewRepository struct {
+	logger *slog.Logger
+}
+
+func NewPostgresReviewRepository(
+	baseLogger *slog.Logger,
+) repository.BaseReviewRepository {
+	logger := baseLogger.With("module", "reviewrepo")
+	return &PostgresReviewRepository{
+		logger: logger,
+	}
+}
+
+// GetReviewsByPrId returns reviews of the currently assigned reviewers of
+// the PR, reviews left by reviewers removed since then are skipped.
+func (p *PostgresReviewRepository) GetReviewsByPrId(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]entity.Review, error) {
+	query := `
+		SELECT r.pr_id, r.user_id, r.decision, r.comment, r.submitted_at
+		FROM pull_requests_reviews r
+		JOIN pull_requests_users pr_u ON pr_u.pr_id = r.pr_id AND pr_u.user_id = r.user_id
+		WHERE r.pr_id = $1
+		ORDER BY r.submitted_at, r.user_id
+	`
+	var reviews []entity.Review
+
+	rows, err := db.Query(ctx, query, prId)
+	if err != nil {
+		p.logger.Debug("failed to GetReviewsByPrId", "prId", prId, "err", err)
+		return nil, errs.ErrInternal("failed to GetReviewsByPrId", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r entity.Review
+		err := rows.Scan(&r.PullRequestId, &r.UserId, &r.Decision, &r.Comment, &r.SubmittedAt)
+		if err != nil {
+			p.logger.Debug("failed to GetReviewsByPrId: scan error", "prId", prId, "err", err)
+			return nil, errs.ErrInternal("failed to GetReviewsByPrId: scan error", err)
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews, nil
+}
+
+func (p *PostgresReviewRepository) GetReviewsByPrIds(
+	ctx context.Context,
+	db repository.Querier,
+	prIds []string,
+) ([]entity.Review, error) {
+	query := `
+		SELECT r.pr_id, r.user_id, r.decision, r.comment, r.submitted_at
+		FROM pull_requests_reviews r
+		JOIN pull_requests_users pr_u ON pr_u.pr_id = r.pr_id AND pr_u.user_id = r.user_id
+		WHERE r.pr_id = ANY($1)
+		ORDER BY r.pr_id, r.submitted_at, r.user_id
+	`
+	var reviews []entity.Review
+
+	rows, err := db.Query(ctx, query, prIds)
+	if err != nil {
+		p.logger.Debug("failed to GetReviewsByPrIds", "prIds", prIds, "err", err)
+		return nil, errs.ErrInternal("failed to GetReviewsByPrIds", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r entity.Review
+		err := rows.Scan(&r.PullRequestId, &r.UserId, &r.Decision, &r.Comment, &r.SubmittedAt)
+		if err != nil {
+			p.logger.Debug("failed to GetReviewsByPrIds: scan error", "prIds", prIds, "err", err)
+			return nil, errs.ErrInternal("failed to GetReviewsByPrIds: scan error", err)
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews, nil
+}
+
+// SetReview stores the decision of a reviewer, replacing the previous one.
+func (p *PostgresReviewRepository) SetReview(
+	ctx context.Context,
+	db repository.Querier,
+	review *entity.Review,
+) error {
+	query := `
+		INSERT INTO pull_requests_reviews (pr_id, user_id, decision, comment)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (pr_id, user_id) DO UPDATE
+		SET decision = EXCLUDED.decision, comment = EXCLUDED.comment, submitted_at = now()
+		RETURNING submitted_at
+	`
+	err := db.QueryRow(
+		ctx,
+		query,
+		review.PullRequestId,
+		review.UserId,
+		review.Decision,
+		review.Comment,
+	).Scan(&review.SubmittedAt)
+	if err != nil {
+		p.logger.Debug("failed to SetReview", "review", review, "err", err)
+		return errs.ErrInternal("failed to SetReview", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+type PostgresReviewerRuleRepository struct {
+	logger *slog.Logger
+}
+
+func NewPostgresReviewerRuleRepository(
+	baseLogger *slog.Logger,
+) repository.BaseReviewerRuleRepository {
+	logger := baseLogger.With("module", "rulerepo")
+	return &PostgresReviewerRuleRepository{
+		logger: logger,
+	}
+}
+
+func (p *PostgresReviewerRuleRepository) GetRulesByAuthorId(
+	ctx context.Context,
+	db repository.Querier,
+	authorId string,
+) ([]entity.ReviewerRule, error) {
+	query := `
+		SELECT author_id, reviewer_id, kind FROM reviewer_rules
+		WHERE author_id = $1
+		ORDER BY reviewer_id
+	`
+	var rules []entity.ReviewerRule
+
+	rows, err := db.Query(ctx, query, authorId)
+	if err != nil {
+		p.logger.Debug("failed to GetRulesByAuthorId", "authorId", authorId, "err", err)
+		return nil, errs.ErrInternal("failed to GetRulesByAuthorId", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rule entity.ReviewerRule
+		err := rows.Scan(&rule.AuthorId, &rule.ReviewerId, &rule.Kind)
+		if err != nil {
+			p.logger.Debug(
+				"failed to GetRulesByAuthorId: scan error",
+				"authorId",
+				authorId,
+				"err",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetRulesByAuthorId: scan error", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (p *PostgresReviewerRuleRepository) SetRule(
+	ctx context.Context,
+	db repository.Querier,
+	rule *entity.ReviewerRule,
+) error {
+	query := `
+		INSERT INTO reviewer_rules (author_id, reviewer_id, kind)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (author_id, reviewer_id) DO UPDATE SET kind = EXCLUDED.kind
+	`
+	_, err := db.Exec(ctx, query, rule.AuthorId, rule.ReviewerId, rule.Kind)
+	if err != nil {
+		p.logger.Debug("failed to SetRule", "rule", rule, "err", err)
+		return errs.ErrInternal("failed to SetRule", err)
+	}
+	return nil
+}
+
+func (p *PostgresReviewerRuleRepository) RemoveRule(
+	ctx context.Context,
+	db repository.Querier,
+	authorId string,
+	reviewerId string,
+) error {
+	query := `
+		DELETE FROM reviewer_rules
+		WHERE author_id = $1 AND reviewer_id = $2
+	`
+	ct, err := db.Exec(ctx, query, authorId, reviewerId)
+	if err != nil {
+		p.logger.Debug(
+			"failed to RemoveRule",
+			"authorId",
+			authorId,
+			"reviewerId",
+			reviewerId,
+			"err",
+			err,
+		)
+		return errs.ErrInternal("failed to RemoveRule", err)
+	}
+	if ct.RowsAffected() == 0 {
+		p.logger.Debug(
+			"failed to RemoveRule: not found",
+			"authorId",
+			authorId,
+			"reviewerId",
+			reviewerId,
+		)
+		return errs.ErrNotFound(
+			"reviewer rule",
+			"authorId and reviewerId",
+			fmt.Sprintf("%s, %s", authorId, reviewerId),
+		)
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
 	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
@@ -29,13 +31,25 @@ func (p *PostgresTeamRepository) GetTeam(
 	teamName string,
 ) (*entity.Team, error) {
 	query := `
-		SELECT name FROM teams
+		SELECT name, reviewer_strategy, min_reviewers, max_reviewers, max_open_reviews,
+		review_window_days, required_approvals, require_resolved_comments, round_robin_cursor
+		FROM teams
 		WHERE name = $1
 	`
 
 	var team entity.Team
 
-	err := db.QueryRow(ctx, query, teamName).Scan(&team.TeamName)
+	err := db.QueryRow(ctx, query, teamName).Scan(
+		&team.TeamName,
+		&team.ReviewerStrategy,
+		&team.MinReviewers,
+		&team.MaxReviewers,
+		&team.MaxOpenReviews,
+		&team.ReviewWindowDays,
+		&team.RequiredApprovals,
+		&team.RequireResolvedComments,
+		&team.RoundRobinCursor,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			p.logger.Debug("failed to GetTeam: not found", "teamName", teamName)
@@ -54,13 +68,403 @@ func (p *PostgresTeamRepository) AddTeam(
 ) error {
 	query := `
 		INSERT INTO teams
-		(name) VALUES ($1);
+		(
+			name, reviewer_strategy, min_reviewers, max_reviewers, max_open_reviews,
+			review_window_days, required_approvals, require_resolved_comments
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
 	`
 
-	_, err := db.Exec(ctx, query, new.TeamName)
+	strategy := new.ReviewerStrategy
+	if strategy == "" {
+		strategy = entity.DefaultReviewerStrategy
+	}
+	maxReviewers := new.MaxReviewers
+	if maxReviewers == 0 {
+		maxReviewers = entity.DefaultMaxReviewers
+	}
+	reviewWindowDays := new.ReviewWindowDays
+	if reviewWindowDays == 0 {
+		reviewWindowDays = entity.DefaultReviewWindowDays
+	}
+	_, err := db.Exec(
+		ctx,
+		query,
+		new.TeamName,
+		strategy,
+		new.MinReviewers,
+		maxReviewers,
+		new.MaxOpenReviews,
+		reviewWindowDays,
+		new.RequiredApprovals,
+		new.RequireResolvedComments,
+	)
 	if err != nil {
 		p.logger.Debug("failed to AddTeam", "teamName", new.TeamName, "err", err)
 		return errs.ErrInternal("failed to AddTeam", err)
 	}
 	return nil
 }
+
+func (p *PostgresTeamRepository) UpdateTeam(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+	update *entity.TeamUpdate,
+) error {
+	if *update == (entity.TeamUpdate{}) {
+		return errs.ErrBadFilter("at least one team setting is required")
+	}
+
+	query := `
+		UPDATE teams
+		SET 
+	`
+
+	currUpdate := 1
+	values := make([]string, 0)
+	args := make([]interface{}, 0)
+	if update.ReviewerStrategy != nil {
+		values = append(values, fmt.Sprintf("reviewer_strategy = $%d", currUpdate))
+		args = append(args, *update.ReviewerStrategy)
+		currUpdate++
+	}
+	if update.MinReviewers != nil {
+		values = append(values, fmt.Sprintf("min_reviewers = $%d", currUpdate))
+		args = append(args, *update.MinReviewers)
+		currUpdate++
+	}
+	if update.MaxReviewers != nil {
+		values = append(values, fmt.Sprintf("max_reviewers = $%d", currUpdate))
+		args = append(args, *update.MaxReviewers)
+		currUpdate++
+	}
+	if update.ClearMaxOpenReviews {
+		values = append(values, "max_open_reviews = NULL")
+	} else if update.MaxOpenReviews != nil {
+		values = append(values, fmt.Sprintf("max_open_reviews = $%d", currUpdate))
+		args = append(args, *update.MaxOpenReviews)
+		currUpdate++
+	}
+	if update.ReviewWindowDays != nil {
+		values = append(values, fmt.Sprintf("review_window_days = $%d", currUpdate))
+		args = append(args, *update.ReviewWindowDays)
+		currUpdate++
+	}
+	if update.RequiredApprovals != nil {
+		values = append(values, fmt.Sprintf("required_approvals = $%d", currUpdate))
+		args = append(args, *update.RequiredApprovals)
+		currUpdate++
+	}
+	if update.RequireResolvedComments != nil {
+		values = append(values, fmt.Sprintf("require_resolved_comments = $%d", currUpdate))
+		args = append(args, *update.RequireResolvedComments)
+		currUpdate++
+	}
+	query = fmt.Sprintf(
+		"%s %s %s",
+		query,
+		strings.Join(values, ", "),
+		fmt.Sprintf("WHERE name = $%d", currUpdate),
+	)
+	args = append(args, teamName)
+
+	ct, err := db.Exec(ctx, query, args...)
+	if err != nil {
+		p.logger.Debug("failed to UpdateTeam", "query", query, "args", args, "error", err)
+		return errs.ErrInternal("failed to UpdateTeam", err)
+	}
+	if ct.RowsAffected() == 0 {
+		p.logger.Debug("failed to UpdateTeam: not found", "teamName", teamName)
+		return errs.ErrNotFound("team", "name", teamName)
+	}
+	return nil
+}
+
+func (p *PostgresTeamRepository) SetRoundRobinCursor(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+	cursor string,
+) error {
+	query := `
+		UPDATE teams
+		SET round_robin_cursor = $2
+		WHERE name = $1
+	`
+	ct, err := db.Exec(ctx, query, teamName, cursor)
+	if err != nil {
+		p.logger.Debug("failed to SetRoundRobinCursor", "teamName", teamName, "err", err)
+		return errs.ErrInternal("failed to SetRoundRobinCursor", err)
+	}
+	if ct.RowsAffected() == 0 {
+		p.logger.Debug("failed to SetRoundRobinCursor: not found", "teamName", teamName)
+		return errs.ErrNotFound("team", "name", teamName)
+	}
+	return nil
+}
+
+func (p *PostgresTeamRepository) GetFallbackTeams(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+) ([]entity.Team, error) {
+	query := `
+		SELECT t.name, t.reviewer_strategy, t.min_reviewers, t.max_reviewers, t.max_open_reviews,
+		t.review_window_days, t.required_approvals, t.require_resolved_comments,
+		t.round_robin_cursor
+		FROM teams t
+		JOIN team_fallbacks tf ON t.name = tf.fallback_team_name
+		WHERE tf.team_name = $1
+		ORDER BY tf.position
+	`
+	var teams []entity.Team
+
+	rows, err := db.Query(ctx, query, teamName)
+	if err != nil {
+		p.logger.Debug("failed to GetFallbackTeams", "teamName", teamName, "err", err)
+		return nil, errs.ErrInternal("failed to GetFallbackTeams", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var team entity.Team
+		err := rows.Scan(
+			&team.TeamName,
+			&team.ReviewerStrategy,
+			&team.MinReviewers,
+			&team.MaxReviewers,
+			&team.MaxOpenReviews,
+			&team.ReviewWindowDays,
+			&team.RequiredApprovals,
+			&team.RequireResolvedComments,
+			&team.RoundRobinCursor,
+		)
+		if err != nil {
+			p.logger.Debug("failed to GetFallbackTeams: scan error", "teamName", teamName, "err", err)
+			return nil, errs.ErrInternal("failed to GetFallbackTeams: scan error", err)
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+func (p *PostgresTeamRepository) SetFallbackTeams(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+	fallbacks []string,
+) error {
+	query := `
+		DELETE FROM team_fallbacks
+		WHERE team_name = $1
+	`
+	_, err := db.Exec(ctx, query, teamName)
+	if err != nil {
+		p.logger.Debug("failed to SetFallbackTeams: delete error", "teamName", teamName, "err", err)
+		return errs.ErrInternal("failed to SetFallbackTeams: delete error", err)
+	}
+	if len(fallbacks) == 0 {
+		return nil
+	}
+
+	query = `
+		INSERT INTO team_fallbacks (team_name, fallback_team_name, position)
+		SELECT $1, f.name, f.position FROM unnest($2::varchar[]) WITH ORDINALITY AS f(name, position)
+	`
+	_, err = db.Exec(ctx, query, teamName, fallbacks)
+	if err != nil {
+		p.logger.Debug(
+			"failed to SetFallbackTeams",
+			"teamName",
+			teamName,
+			"fallbacks",
+			fallbacks,
+			"err",
+			err,
+		)
+		return errs.ErrInternal("failed to SetFallbackTeams", err)
+	}
+	return nil
+}
+
+func (p *PostgresTeamRepository) GetCodeOwnerRules(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+) ([]entity.CodeOwnerRule, error) {
+	query := `
+		SELECT team_name, position, pattern, user_ids, team_names FROM code_owner_rules
+		WHERE team_name = $1
+		ORDER BY position
+	`
+	var rules []entity.CodeOwnerRule
+
+	rows, err := db.Query(ctx, query, teamName)
+	if err != nil {
+		p.logger.Debug("failed to GetCodeOwnerRules", "teamName", teamName, "err", err)
+		return nil, errs.ErrInternal("failed to GetCodeOwnerRules", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rule entity.CodeOwnerRule
+		err := rows.Scan(
+			&rule.TeamName,
+			&rule.Position,
+			&rule.Pattern,
+			&rule.UserIds,
+			&rule.TeamNames,
+		)
+		if err != nil {
+			p.logger.Debug(
+				"failed to GetCodeOwnerRules: scan error",
+				"teamName",
+				teamName,
+				"err",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetCodeOwnerRules: scan error", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (p *PostgresTeamRepository) SetCodeOwnerRules(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+	rules []entity.CodeOwnerRule,
+) error {
+	query := `
+		DELETE FROM code_owner_rules
+		WHERE team_name = $1
+	`
+	_, err := db.Exec(ctx, query, teamName)
+	if err != nil {
+		p.logger.Debug(
+			"failed to SetCodeOwnerRules: delete error",
+			"teamName",
+			teamName,
+			"err",
+			err,
+		)
+		return errs.ErrInternal("failed to SetCodeOwnerRules: delete error", err)
+	}
+
+	query = `
+		INSERT INTO code_owner_rules (team_name, position, pattern, user_ids, team_names)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for i, rule := range rules {
+		userIds := rule.UserIds
+		if userIds == nil {
+			userIds = []string{}
+		}
+		teamNames := rule.TeamNames
+		if teamNames == nil {
+			teamNames = []string{}
+		}
+
+		_, err := db.Exec(ctx, query, teamName, i+1, rule.Pattern, userIds, teamNames)
+		if err != nil {
+			p.logger.Debug(
+				"failed to SetCodeOwnerRules",
+				"teamName",
+				teamName,
+				"pattern",
+				rule.Pattern,
+				"err",
+				err,
+			)
+			return errs.ErrInternal("failed to SetCodeOwnerRules", err)
+		}
+	}
+	return nil
+}
+
+func (p *PostgresTeamRepository) GetRepository(
+	ctx context.Context,
+	db repository.Querier,
+	repositoryName string,
+) (*entity.Repository, error) {
+	query := `
+		SELECT name, team_name, route_reviews_to_owner FROM repositories
+		WHERE name = $1
+	`
+	var repo entity.Repository
+	err := db.QueryRow(ctx, query, repositoryName).Scan(
+		&repo.RepositoryName,
+		&repo.TeamName,
+		&repo.RouteReviewsToOwner,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.logger.Debug("failed to GetRepository: not found", "repositoryName", repositoryName)
+			return nil, errs.ErrNotFound("repository", "name", repositoryName)
+		}
+		p.logger.Debug("failed to GetRepository", "repositoryName", repositoryName, "err", err)
+		return nil, errs.ErrInternal("failed to GetRepository", err)
+	}
+	return &repo, nil
+}
+
+func (p *PostgresTeamRepository) GetRepositoriesByTeamName(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+) ([]entity.Repository, error) {
+	query := `
+		SELECT name, team_name, route_reviews_to_owner FROM repositories
+		WHERE team_name = $1
+		ORDER BY name
+	`
+	var repos []entity.Repository
+
+	rows, err := db.Query(ctx, query, teamName)
+	if err != nil {
+		p.logger.Debug("failed to GetRepositoriesByTeamName", "teamName", teamName, "err", err)
+		return nil, errs.ErrInternal("failed to GetRepositoriesByTeamName", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var repo entity.Repository
+		err := rows.Scan(&repo.RepositoryName, &repo.TeamName, &repo.RouteReviewsToOwner)
+		if err != nil {
+			p.logger.Debug(
+				"failed to GetRepositoriesByTeamName: scan error",
+				"teamName",
+				teamName,
+				"err",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetRepositoriesByTeamName: scan error", err)
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// SetRepository adds a repository or replaces the owner and the review
+// routing of an existing one.
+func (p *PostgresTeamRepository) SetRepository(
+	ctx context.Context,
+	db repository.Querier,
+	repo *entity.Repository,
+) error {
+	query := `
+		INSERT INTO repositories (name, team_name, route_reviews_to_owner)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE
+		SET team_name = EXCLUDED.team_name,
+			route_reviews_to_owner = EXCLUDED.route_reviews_to_owner
+	`
+	_, err := db.Exec(ctx, query, repo.RepositoryName, repo.TeamName, repo.RouteReviewsToOwner)
+	if err != nil {
+		p.logger.Debug("failed to SetRepository", "repository", repo, "err", err)
+		return errs.ErrInternal("failed to SetRepository", err)
+	}
+	return nil
+}
@@ -31,7 +31,7 @@ func (p *PostgresUserRepository) GetById(
 	id string,
 ) (*entity.User, error) {
 	query := `
-        SELECT id, username, team_name, is_active
+        SELECT id, username, team_name, is_active, max_open_reviews
         FROM users
         WHERE id = $1
     `
@@ -42,6 +42,7 @@ func (p *PostgresUserRepository) GetById(
 		&result.Username,
 		&result.TeamName,
 		&result.IsActive,
+		&result.MaxOpenReviews,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -60,7 +61,7 @@ func (p *PostgresUserRepository) GetByTeamName(
 	teamName string,
 ) ([]entity.User, error) {
 	query := `
-        SELECT id, username, team_name, is_active
+        SELECT id, username, team_name, is_active, max_open_reviews
         FROM users
         WHERE team_name = $1
     `
@@ -80,7 +81,8 @@ func (p *PostgresUserRepository) GetByTeamName(
 			&user.Id,
 			&user.Username,
 			&user.TeamName,
-			&user.IsActive)
+			&user.IsActive,
+			&user.MaxOpenReviews)
 		if err != nil {
 			p.logger.Debug(
 				"failed to GetByTeamName: scan error",
@@ -102,9 +104,9 @@ func (p *PostgresUserRepository) GetActiveByTeamName(
 	teamName string,
 ) ([]entity.User, error) {
 	query := `
-        SELECT id, username, team_name, is_active
-        FROM users
-        WHERE team_name = $1 AND is_active = true
+        SELECT id, username, team_name, is_active, max_open_reviews
+        FROM users u
+        WHERE team_name = $1 AND ` + availableCondition + `
     `
 	var result []entity.User
 
@@ -122,7 +124,8 @@ func (p *PostgresUserRepository) GetActiveByTeamName(
 			&user.Id,
 			&user.Username,
 			&user.TeamName,
-			&user.IsActive)
+			&user.IsActive,
+			&user.MaxOpenReviews)
 		if err != nil {
 			p.logger.Debug(
 				"failed to GetActiveByTeamName: scan error",
@@ -144,7 +147,7 @@ func (p *PostgresUserRepository) GetReviewersByPrId(
 	prId string,
 ) ([]entity.User, error) {
 	query := `
-		SELECT id, username, team_name, is_active
+		SELECT id, username, team_name, is_active, max_open_reviews
 		FROM users u
 		JOIN pull_requests_users pr_u ON u.id = pr_u.user_id
 		WHERE pr_u.pr_id = $1
@@ -164,6 +167,7 @@ func (p *PostgresUserRepository) GetReviewersByPrId(
 			&user.Username,
 			&user.TeamName,
 			&user.IsActive,
+			&user.MaxOpenReviews,
 		)
 		if err != nil {
 			p.logger.Debug("failed to GetReviewersByPrId", "prId", prId, "error", err)
@@ -174,6 +178,128 @@ func (p *PostgresUserRepository) GetReviewersByPrId(
 	return result, nil
 }
 
+func (p *PostgresUserRepository) GetSkillsByUserIds(
+	ctx context.Context,
+	db repository.Querier,
+	userIds []string,
+) ([]entity.UserSkill, error) {
+	query := `
+		SELECT user_id, skill FROM users_skills
+		WHERE user_id = ANY($1)
+		ORDER BY user_id, skill
+	`
+	var result []entity.UserSkill
+
+	rows, err := db.Query(ctx, query, userIds)
+	if err != nil {
+		p.logger.Debug("failed to GetSkillsByUserIds", "userIds", userIds, "error", err)
+		return nil, errs.ErrInternal("failed to GetSkillsByUserIds", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var skill entity.UserSkill
+		if err := rows.Scan(&skill.UserId, &skill.Skill); err != nil {
+			p.logger.Debug(
+				"failed to GetSkillsByUserIds: scan error",
+				"userIds",
+				userIds,
+				"error",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetSkillsByUserIds: scan error", err)
+		}
+		result = append(result, skill)
+	}
+	return result, nil
+}
+
+func (p *PostgresUserRepository) GetUserIdsBySkills(
+	ctx context.Context,
+	db repository.Querier,
+	skills []string,
+) ([]string, error) {
+	query := `
+		SELECT DISTINCT user_id FROM users_skills
+		WHERE skill = ANY($1)
+		ORDER BY user_id
+	`
+	var result []string
+
+	rows, err := db.Query(ctx, query, skills)
+	if err != nil {
+		p.logger.Debug("failed to GetUserIdsBySkills", "skills", skills, "error", err)
+		return nil, errs.ErrInternal("failed to GetUserIdsBySkills", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			p.logger.Debug(
+				"failed to GetUserIdsBySkills: scan error",
+				"skills",
+				skills,
+				"error",
+				err,
+			)
+			return nil, errs.ErrInternal("failed to GetUserIdsBySkills: scan error", err)
+		}
+		result = append(result, userId)
+	}
+	return result, nil
+}
+
+func (p *PostgresUserRepository) SetSkills(
+	ctx context.Context,
+	db repository.Querier,
+	userId string,
+	skills []string,
+) error {
+	_, err := db.Exec(ctx, "DELETE FROM users_skills WHERE user_id = $1", userId)
+	if err != nil {
+		p.logger.Debug("failed to SetSkills: delete error", "userId", userId, "error", err)
+		return errs.ErrInternal("failed to SetSkills: delete error", err)
+	}
+	if len(skills) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO users_skills (user_id, skill)
+		SELECT $1, unnest($2::varchar[])
+		ON CONFLICT DO NOTHING
+	`
+	_, err = db.Exec(ctx, query, userId, skills)
+	if err != nil {
+		p.logger.Debug("failed to SetSkills", "userId", userId, "skills", skills, "error", err)
+		return errs.ErrInternal("failed to SetSkills", err)
+	}
+	return nil
+}
+
+func (p *PostgresUserRepository) SetMaxOpenReviews(
+	ctx context.Context,
+	db repository.Querier,
+	userId string,
+	limit *int,
+) error {
+	query := `
+		UPDATE users SET max_open_reviews = $2
+		WHERE id = $1
+	`
+	ct, err := db.Exec(ctx, query, userId, limit)
+	if err != nil {
+		p.logger.Debug("failed to SetMaxOpenReviews", "userId", userId, "error", err)
+		return errs.ErrInternal("failed to SetMaxOpenReviews", err)
+	}
+	if ct.RowsAffected() == 0 {
+		p.logger.Debug("failed to SetMaxOpenReviews: not found", "userId", userId)
+		return errs.ErrNotFound("user", "id", userId)
+	}
+	return nil
+}
+
 func (p *PostgresUserRepository) AddUsers(
 	ctx context.Context,
 	db repository.Querier,
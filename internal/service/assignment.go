@@ -0,0 +1,840 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+type selectedReviewer struct {
+	UserId   string
+	TeamName string
+	Owner    bool
+}
+
+// reviewerTeams returns primary followed by the fallback teams of owner,
+// without duplicates.
+func (s *PullRequestService) reviewerTeams(
+	ctx context.Context,
+	db repository.Querier,
+	owner *entity.Team,
+	primary ...*entity.Team,
+) ([]entity.Team, error) {
+	fallbacks, err := s.teamRepo.GetFallbackTeams(ctx, db, owner.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]entity.Team, 0, len(primary)+len(fallbacks))
+	for _, team := range primary {
+		teams = append(teams, *team)
+	}
+	for _, team := range fallbacks {
+		if !slices.ContainsFunc(teams, func(t entity.Team) bool {
+			return t.TeamName == team.TeamName
+		}) {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
+
+// assignmentRequest holds the constraints shared by every selection step
+// of a single assignment. Picked reviewers are excluded as they are chosen.
+// skilled are users whose skills cover at least one of the PR labels.
+// skipped collects candidates left out of selection by reason and reasons
+// records why every picked reviewer was chosen. All steps draw from one
+// generator seeded with seed and are recorded in steps, so the assignment
+// can be replayed later. cursors holds the round robin cursors the steps
+// moved by team name. They are stored with the assignment, so a preview,
+// which is never saved, leaves the cursors of teams untouched.
+type assignmentRequest struct {
+	authorId     string
+	excluded     []string
+	ruleExcluded []string
+	preferred    []string
+	labels       []string
+	skilled      []string
+	skipped      map[string][]string
+	reasons      map[string]string
+	seed         uint64
+	rng          *rand.Rand
+	steps        []entity.AssignmentStep
+	cursors      map[string]string
+}
+
+// shortage returns the error reported when req could not pick enough
+// reviewers: ErrReviewersAtCapacity if some candidates were skipped only
+// because of their limit, err otherwise.
+func (req *assignmentRequest) shortage(err error) error {
+	if len(req.skipped[entity.ExcludedAtCapacity]) > 0 {
+		return errs.ErrReviewersAtCapacity
+	}
+	return err
+}
+
+func (req *assignmentRequest) skip(reason string, userId string) {
+	if !slices.Contains(req.skipped[reason], userId) {
+		req.skipped[reason] = append(req.skipped[reason], userId)
+	}
+}
+
+// exclude reports whether member can't be picked by req and records the
+// reason in skipped. Reviewers already picked or assigned are excluded
+// without a reason.
+func (req *assignmentRequest) exclude(member entity.UserStats) bool {
+	if slices.Contains(req.excluded, member.Id) {
+		switch {
+		case member.Id == req.authorId:
+			req.skip(entity.ExcludedAuthor, member.Id)
+		case slices.Contains(req.ruleExcluded, member.Id):
+			req.skip(entity.ExcludedByRule, member.Id)
+		}
+		return true
+	}
+	if atCapacity(member) {
+		req.skip(entity.ExcludedAtCapacity, member.Id)
+		return true
+	}
+	return false
+}
+
+// skippedReason returns the reason userId was skipped by req, empty if it
+// was not.
+func (req *assignmentRequest) skippedReason(userId string) string {
+	for _, reason := range []string{
+		entity.ExcludedAuthor,
+		entity.ExcludedByRule,
+		entity.ExcludedAtCapacity,
+	} {
+		if slices.Contains(req.skipped[reason], userId) {
+			return reason
+		}
+	}
+	return ""
+}
+
+// nextSeed draws the seed of an assignment that is going to be stored from
+// the shared source.
+func (s *PullRequestService) nextSeed() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.source.Uint64()
+}
+
+func (s *PullRequestService) newAssignmentRequest(
+	ctx context.Context,
+	db repository.Querier,
+	seed uint64,
+	authorId string,
+	labels []string,
+	excluded ...string,
+) (*assignmentRequest, error) {
+	rules, err := s.ruleRepo.GetRulesByAuthorId(ctx, db, authorId)
+	if err != nil {
+		return nil, err
+	}
+	var skilled []string
+	if len(labels) > 0 {
+		skilled, err = s.userRepo.GetUserIdsBySkills(ctx, db, labels)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req := &assignmentRequest{
+		authorId: authorId,
+		excluded: append([]string{authorId}, excluded...),
+		labels:   labels,
+		skilled:  skilled,
+		skipped:  make(map[string][]string),
+		reasons:  make(map[string]string),
+		cursors:  make(map[string]string),
+		seed:     seed,
+		rng:      NewAssignmentRand(seed),
+	}
+	for _, rule := range rules {
+		switch rule.Kind {
+		case entity.RuleExclude:
+			req.excluded = append(req.excluded, rule.ReviewerId)
+			req.ruleExcluded = append(req.ruleExcluded, rule.ReviewerId)
+		case entity.RulePrefer:
+			req.preferred = append(req.preferred, rule.ReviewerId)
+		}
+	}
+	return req, nil
+}
+
+// pick selects up to count candidates with the team strategy, taking
+// preferred candidates first, then candidates with matching skills, and
+// never returning excluded ones or ones at their open reviews limit.
+func (s *PullRequestService) pick(
+	team *entity.Team,
+	members []entity.UserStats,
+	req *assignmentRequest,
+	count int,
+) []string {
+	var preferred, skilled, others []ReviewerCandidate
+	for _, member := range members {
+		if req.exclude(member) {
+			continue
+		}
+		candidate := ReviewerCandidate{
+			UserId:        member.Id,
+			OpenReviews:   member.OpenPullRequestsCount,
+			RecentReviews: member.RecentReviewsCount,
+		}
+		switch {
+		case slices.Contains(req.preferred, member.Id):
+			preferred = append(preferred, candidate)
+		case slices.Contains(req.skilled, member.Id):
+			skilled = append(skilled, candidate)
+		default:
+			others = append(others, candidate)
+		}
+	}
+
+	tiers := []struct {
+		candidates []ReviewerCandidate
+		reason     string
+	}{
+		{preferred, entity.ReasonPreferred},
+		{skilled, entity.ReasonSkillMatch},
+		{others, ""},
+	}
+	var picked []string
+	for _, tier := range tiers {
+		if len(picked) >= count {
+			break
+		}
+		picked = append(
+			picked,
+			s.selectStep(team, req, tier.candidates, count-len(picked), tier.reason)...,
+		)
+	}
+	req.excluded = append(req.excluded, picked...)
+	return picked
+}
+
+func atCapacity(member entity.UserStats) bool {
+	return member.MaxOpenReviews != nil && member.OpenPullRequestsCount >= *member.MaxOpenReviews
+}
+
+// selectStep runs the team selector over candidates and records the call.
+// Picked reviewers are explained by reason, or by the strategy if it is
+// empty.
+func (s *PullRequestService) selectStep(
+	team *entity.Team,
+	req *assignmentRequest,
+	candidates []ReviewerCandidate,
+	count int,
+	reason string,
+) []string {
+	if len(candidates) == 0 || count <= 0 {
+		return nil
+	}
+	selector, strategy := s.selectorFor(team)
+
+	var cursor string
+	if strategy == entity.StrategyRoundRobin {
+		var ok bool
+		if cursor, ok = req.cursors[team.TeamName]; !ok {
+			cursor = team.RoundRobinCursor
+		}
+	}
+	picked := selector.Select(req.rng, cursor, candidates, count)
+	if strategy == entity.StrategyRoundRobin && len(picked) > 0 {
+		req.cursors[team.TeamName] = picked[len(picked)-1]
+	}
+
+	step := entity.AssignmentStep{
+		TeamName:   team.TeamName,
+		Strategy:   strategy,
+		Cursor:     cursor,
+		Count:      count,
+		Candidates: make([]entity.AssignmentCandidate, len(candidates)),
+		Picked:     picked,
+	}
+	for i, c := range candidates {
+		step.Candidates[i] = entity.AssignmentCandidate{
+			UserId:        c.UserId,
+			OpenReviews:   c.OpenReviews,
+			RecentReviews: c.RecentReviews,
+		}
+	}
+	req.steps = append(req.steps, step)
+
+	if reason == "" {
+		reason = strategyReasons[strategy]
+	}
+	for _, id := range picked {
+		req.reasons[id] = reason
+	}
+	return picked
+}
+
+// manualStep records member, chosen by hand, as a step of req offering
+// the selector only member, so replaying req reproduces the choice, and
+// explains the pick as manual.
+func (s *PullRequestService) manualStep(
+	team *entity.Team,
+	req *assignmentRequest,
+	member entity.UserStats,
+) {
+	_, strategy := s.selectorFor(team)
+	req.steps = append(req.steps, entity.AssignmentStep{
+		TeamName: team.TeamName,
+		Strategy: strategy,
+		Count:    1,
+		Candidates: []entity.AssignmentCandidate{{
+			UserId:        member.Id,
+			OpenReviews:   member.OpenPullRequestsCount,
+			RecentReviews: member.RecentReviewsCount,
+		}},
+		Picked: []string{member.Id},
+	})
+	req.reasons[member.Id] = entity.ReasonManual
+}
+
+var strategyReasons = map[string]string{
+	entity.StrategyRandom:        entity.ReasonRandomPick,
+	entity.StrategyRoundRobin:    entity.ReasonRoundRobin,
+	entity.StrategyLeastLoaded:   entity.ReasonLeastLoaded,
+	entity.StrategyWeighted:      entity.ReasonWeightedPick,
+	entity.StrategyRecentHistory: entity.ReasonRecentHistory,
+}
+
+// explainAssignment describes why selected were chosen by req: the
+// strategy of the review team, the number of distinct candidates the
+// selectors were given, the reason of every reviewer and the candidates
+// left out of the pool.
+func (s *PullRequestService) explainAssignment(
+	team *entity.Team,
+	req *assignmentRequest,
+	selected []selectedReviewer,
+) *entity.AssignmentExplanationDTO {
+	_, strategy := s.selectorFor(team)
+
+	var pool []string
+	for _, step := range req.steps {
+		for _, c := range step.Candidates {
+			if !slices.Contains(pool, c.UserId) {
+				pool = append(pool, c.UserId)
+			}
+		}
+	}
+
+	result := &entity.AssignmentExplanationDTO{
+		Strategy:  strategy,
+		PoolSize:  len(pool),
+		Reviewers: make([]entity.ReviewerReasonDTO, len(selected)),
+	}
+	for i, r := range selected {
+		reason := req.reasons[r.UserId]
+		if r.Owner {
+			reason = entity.ReasonOwnershipMatch
+		}
+		result.Reviewers[i] = entity.ReviewerReasonDTO{
+			UserId:   r.UserId,
+			TeamName: r.TeamName,
+			Reason:   reason,
+		}
+	}
+	for _, reason := range []string{
+		entity.ExcludedAuthor,
+		entity.ExcludedByRule,
+		entity.ExcludedAtCapacity,
+	} {
+		if ids := req.skipped[reason]; len(ids) > 0 {
+			result.Constraints = append(result.Constraints, entity.PoolConstraintDTO{
+				Reason:  reason,
+				UserIds: ids,
+			})
+		}
+	}
+	return result
+}
+
+// initialAssignment is the planned set of reviewers of a new PR.
+type initialAssignment struct {
+	req      *assignmentRequest
+	team     *entity.Team
+	teams    []entity.Team
+	selected []selectedReviewer
+}
+
+// planAssignment selects reviewers for a new PR of authorId in
+// repositoryName without changing anything: code owners of paths and an
+// expert matching labels first, then up to the maximum of the review team
+// from the team and its fallbacks. Owners never exceed the maximum. All
+// steps draw from a generator seeded with seed.
+func (s *PullRequestService) planAssignment(
+	ctx context.Context,
+	db repository.Querier,
+	seed uint64,
+	authorId string,
+	repositoryName *string,
+	paths []string,
+	labels []string,
+) (*initialAssignment, error) {
+	author, err := s.userRepo.GetById(ctx, db, authorId)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.reviewTeam(ctx, db, author, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.reviewerTeams(ctx, db, team, team)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.newAssignmentRequest(ctx, db, seed, author.Id, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := s.selectOwners(ctx, db, team, paths, req, nil, team.MaxReviewers)
+	if err != nil {
+		return nil, err
+	}
+	experts, err := s.selectExpert(
+		ctx,
+		db,
+		team,
+		req,
+		selectedIds(owners),
+		team.MaxReviewers-len(owners),
+	)
+	if err != nil {
+		return nil, err
+	}
+	owners = append(owners, experts...)
+
+	selected, err := s.selectReviewers(ctx, db, teams, req, team.MaxReviewers-len(owners))
+	if err != nil {
+		return nil, err
+	}
+
+	return &initialAssignment{
+		req:      req,
+		team:     team,
+		teams:    teams,
+		selected: append(owners, selected...),
+	}, nil
+}
+
+// reviewerReplacement is the planned replacement of a single reviewer.
+// current are the reviewers that stay on the PR.
+type reviewerReplacement struct {
+	req      *assignmentRequest
+	selected []selectedReviewer
+	current  []string
+	team     *entity.Team
+	labels   []string
+}
+
+// planReplacement selects reviewers replacing oldReviewerId on pr without
+// changing anything. Besides one replacement it picks as many reviewers as
+// needed to reach the minimum of the review team.
+func (s *PullRequestService) planReplacement(
+	ctx context.Context,
+	db repository.Querier,
+	pr *entity.PullRequest,
+	oldReviewerId string,
+) (*reviewerReplacement, error) {
+	reviewers, err := s.userRepo.GetReviewersByPrId(ctx, db, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	var oldReviewer *entity.User
+	var current []string
+	for _, u := range reviewers {
+		if u.Id == oldReviewerId {
+			oldReviewer = &u
+		} else {
+			current = append(current, u.Id)
+		}
+	}
+	if oldReviewer == nil {
+		return nil, errs.ErrUserNotAssigned
+	}
+
+	author, err := s.userRepo.GetById(ctx, db, pr.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.reviewTeam(ctx, db, author, pr.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+	reviewerTeam, err := s.teamRepo.GetTeam(ctx, db, oldReviewer.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	count := 1
+	if missing := team.MinReviewers - len(current); missing > count {
+		count = missing
+	}
+
+	teams, err := s.reviewerTeams(ctx, db, team, reviewerTeam, team)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := s.prRepo.GetPullRequestPaths(ctx, db, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.prRepo.GetPullRequestLabels(ctx, db, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.newAssignmentRequest(
+		ctx,
+		db,
+		s.nextSeed(),
+		pr.AuthorId,
+		labels,
+		append([]string{oldReviewerId}, current...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := s.selectOwners(ctx, db, team, paths, req, current, count)
+	if err != nil {
+		return nil, err
+	}
+	experts, err := s.selectExpert(
+		ctx,
+		db,
+		team,
+		req,
+		append(slices.Clone(current), selectedIds(owners)...),
+		count-len(owners),
+	)
+	if err != nil {
+		return nil, err
+	}
+	owners = append(owners, experts...)
+
+	selected, err := s.selectReviewers(ctx, db, teams, req, count-len(owners))
+	if err != nil {
+		return nil, err
+	}
+
+	return &reviewerReplacement{
+		req:      req,
+		selected: append(owners, selected...),
+		current:  current,
+		team:     team,
+		labels:   labels,
+	}, nil
+}
+
+// applyReplacement removes oldReviewerId from pr, assigns the planned
+// reviewers and stores the assignment and the history events.
+func (s *PullRequestService) applyReplacement(
+	ctx context.Context,
+	db repository.Querier,
+	pr *entity.PullRequest,
+	actorId *string,
+	oldReviewerId string,
+	plan *reviewerReplacement,
+) (int64, error) {
+	err := s.prRepo.RemoveReviewerFromPullRequest(ctx, db, pr.Id, oldReviewerId)
+	if err != nil {
+		if errors.Is(err, errs.ErrBaseNotFound) {
+			return 0, errs.ErrUserNotAssigned
+		}
+		return 0, err
+	}
+
+	picked := selectedIds(plan.selected)
+	for _, pId := range picked {
+		err = s.prRepo.AddReviewerToPullRequest(ctx, db, pr.Id, pId)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = s.recordReviewerChanges(
+		ctx,
+		db,
+		pr.Id,
+		actorId,
+		entity.EventReviewerRemoved,
+		oldReviewerId,
+	)
+	if err != nil {
+		return 0, err
+	}
+	err = s.recordReviewerChanges(ctx, db, pr.Id, actorId, entity.EventReviewerAdded, picked...)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.saveAssignment(ctx, db, pr.Id, entity.AssignmentReassign, plan.req, picked)
+}
+
+// saveAssignment stores the seed and steps of req for prId along with the
+// round robin cursors req moved and returns the id of the stored
+// assignment.
+func (s *PullRequestService) saveAssignment(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	kind string,
+	req *assignmentRequest,
+	reviewers []string,
+) (int64, error) {
+	assignment := &entity.Assignment{
+		PullRequestId: prId,
+		Kind:          kind,
+		Seed:          req.seed,
+		Steps:         req.steps,
+		Reviewers:     reviewers,
+	}
+	err := s.assignmentRepo.AddAssignment(ctx, db, assignment)
+	if err != nil {
+		return 0, err
+	}
+	for _, teamName := range slices.Sorted(maps.Keys(req.cursors)) {
+		err = s.teamRepo.SetRoundRobinCursor(ctx, db, teamName, req.cursors[teamName])
+		if err != nil {
+			return 0, err
+		}
+	}
+	return assignment.Id, nil
+}
+
+// replayAssignment repeats the recorded steps of assignment with a
+// generator built from its seed and returns the picked reviewers.
+func replayAssignment(assignment *entity.Assignment) ([]string, error) {
+	rng := NewAssignmentRand(assignment.Seed)
+	replayed := []string{}
+
+	for _, step := range assignment.Steps {
+		selector, err := NewReviewerSelector(step.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]ReviewerCandidate, len(step.Candidates))
+		for i, c := range step.Candidates {
+			candidates[i] = ReviewerCandidate{
+				UserId:        c.UserId,
+				OpenReviews:   c.OpenReviews,
+				RecentReviews: c.RecentReviews,
+			}
+		}
+		replayed = append(replayed, selector.Select(rng, step.Cursor, candidates, step.Count)...)
+	}
+	return replayed, nil
+}
+
+// selectReviewers picks up to count reviewers walking teams in order,
+// moving to the next team only when the previous ones ran out of candidates.
+func (s *PullRequestService) selectReviewers(
+	ctx context.Context,
+	db repository.Querier,
+	teams []entity.Team,
+	req *assignmentRequest,
+	count int,
+) ([]selectedReviewer, error) {
+	selected := make([]selectedReviewer, 0, max(count, 0))
+
+	for _, team := range teams {
+		if len(selected) >= count {
+			break
+		}
+
+		members, err := s.prRepo.GetOpenPullRequestsByTeamMembers(ctx, db, team.TeamName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range s.pick(&team, members, req, count-len(selected)) {
+			selected = append(selected, selectedReviewer{UserId: id, TeamName: team.TeamName})
+		}
+	}
+	return selected, nil
+}
+
+// selectOwners picks one reviewer for every code owner rule of team matching
+// paths, unless one of the owners is already among assigned. It picks at
+// most count reviewers, rules matched after that stay uncovered. Owners
+// may belong to other teams, so they are always picked least loaded first
+// and never move the round robin cursor of team.
+func (s *PullRequestService) selectOwners(
+	ctx context.Context,
+	db repository.Querier,
+	team *entity.Team,
+	paths []string,
+	req *assignmentRequest,
+	assigned []string,
+	count int,
+) ([]selectedReviewer, error) {
+	if len(paths) == 0 || count <= 0 {
+		return nil, nil
+	}
+
+	rules, err := s.teamRepo.GetCodeOwnerRules(ctx, db, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerTeam := entity.Team{
+		TeamName:         team.TeamName,
+		ReviewerStrategy: entity.StrategyLeastLoaded,
+	}
+	covered := slices.Clone(assigned)
+	var selected []selectedReviewer
+
+	for _, rule := range matchCodeOwnerRules(rules, paths) {
+		if len(selected) == count {
+			s.logger.Debug(
+				"code owner rules exceed reviewer count",
+				"teamName",
+				team.TeamName,
+				"pattern",
+				rule.Pattern,
+			)
+			break
+		}
+		owners, err := s.codeOwners(ctx, db, rule)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(owners, func(o entity.UserStats) bool {
+			return slices.Contains(covered, o.Id)
+		}) {
+			continue
+		}
+
+		picked := s.pick(&ownerTeam, owners, req, 1)
+		if len(picked) == 0 {
+			s.logger.Debug(
+				"no available code owner for rule",
+				"teamName",
+				team.TeamName,
+				"pattern",
+				rule.Pattern,
+			)
+			continue
+		}
+		selected = append(selected, selectedReviewer{
+			UserId:   picked[0],
+			TeamName: team.TeamName,
+			Owner:    true,
+		})
+		covered = append(covered, picked[0])
+	}
+	return selected, nil
+}
+
+// selectExpert picks one member of team whose skills cover the PR labels,
+// unless one of assigned already does or count leaves no free slot.
+func (s *PullRequestService) selectExpert(
+	ctx context.Context,
+	db repository.Querier,
+	team *entity.Team,
+	req *assignmentRequest,
+	assigned []string,
+	count int,
+) ([]selectedReviewer, error) {
+	if count <= 0 || len(req.skilled) == 0 || slices.ContainsFunc(assigned, func(id string) bool {
+		return slices.Contains(req.skilled, id)
+	}) {
+		return nil, nil
+	}
+
+	members, err := s.prRepo.GetOpenPullRequestsByTeamMembers(ctx, db, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	experts := slices.DeleteFunc(members, func(m entity.UserStats) bool {
+		return !slices.Contains(req.skilled, m.Id)
+	})
+
+	picked := s.pick(team, experts, req, 1)
+	if len(picked) == 0 {
+		s.logger.Debug("no available reviewer matching labels", "teamName", team.TeamName)
+		return nil, nil
+	}
+	return []selectedReviewer{{UserId: picked[0], TeamName: team.TeamName}}, nil
+}
+
+func (s *PullRequestService) codeOwners(
+	ctx context.Context,
+	db repository.Querier,
+	rule entity.CodeOwnerRule,
+) ([]entity.UserStats, error) {
+	var owners []entity.UserStats
+	if len(rule.UserIds) > 0 {
+		users, err := s.prRepo.GetOpenPullRequestsByUserIds(ctx, db, rule.UserIds)
+		if err != nil {
+			return nil, err
+		}
+		owners = append(owners, users...)
+	}
+	for _, teamName := range rule.TeamNames {
+		members, err := s.prRepo.GetOpenPullRequestsByTeamMembers(ctx, db, teamName)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if !slices.ContainsFunc(owners, func(o entity.UserStats) bool {
+				return o.Id == member.Id
+			}) {
+				owners = append(owners, member)
+			}
+		}
+	}
+	return owners, nil
+}
+
+func (s *PullRequestService) selectorFor(team *entity.Team) (ReviewerSelector, string) {
+	strategy := team.ReviewerStrategy
+	selector, ok := s.selectors[strategy]
+	if !ok {
+		s.logger.Warn(
+			"unknown reviewer strategy, falling back to default",
+			"teamName",
+			team.TeamName,
+			"strategy",
+			team.ReviewerStrategy,
+		)
+		strategy = entity.DefaultReviewerStrategy
+		selector = s.selectors[strategy]
+	}
+	return selector, strategy
+}
+
+func fallbackReviewers(selected []selectedReviewer, ownerTeam string) []entity.FallbackReviewerDTO {
+	var result []entity.FallbackReviewerDTO
+	for _, r := range selected {
+		if !r.Owner && r.TeamName != ownerTeam {
+			result = append(result, entity.FallbackReviewerDTO{
+				UserId:   r.UserId,
+				TeamName: r.TeamName,
+			})
+		}
+	}
+	return result
+}
+
+func selectedIds(selected []selectedReviewer) []string {
+	ids := make([]string, len(selected))
+	for i, r := range selected {
+		ids[i] = r.UserId
+	}
+	return ids
+}
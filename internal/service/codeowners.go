@@ -0,0 +1,88 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+)
+
+// compileCodeOwnerPattern converts a CODEOWNERS-style glob into a regexp.
+// Patterns containing a slash (other than a trailing one) are anchored to
+// the repository root, others match at any depth. `*` and `?` never cross
+// a `/`, `**` does. A pattern matching a directory matches everything in it.
+func compileCodeOwnerPattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" || p == "/" {
+		return nil, errs.ErrInvalidCodeOwnerPattern
+	}
+
+	anchored := strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	p = strings.TrimPrefix(p, "/")
+	if strings.HasSuffix(p, "/") {
+		p += "**"
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(p[i])))
+		}
+	}
+	b.WriteString("(?:/.*)?$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, errs.ErrInvalidCodeOwnerPattern
+	}
+	return re, nil
+}
+
+func MatchCodeOwnerPattern(pattern string, path string) bool {
+	re, err := compileCodeOwnerPattern(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// matchCodeOwnerRules returns the distinct rules owning paths: for every
+// path the last matching rule wins, like in CODEOWNERS files.
+func matchCodeOwnerRules(rules []entity.CodeOwnerRule, paths []string) []entity.CodeOwnerRule {
+	compiled := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		compiled[i], _ = compileCodeOwnerPattern(rule.Pattern)
+	}
+
+	var matched []entity.CodeOwnerRule
+	seen := make(map[int]bool)
+	for _, path := range paths {
+		path = strings.TrimPrefix(path, "/")
+		for i := len(rules) - 1; i >= 0; i-- {
+			if compiled[i] != nil && compiled[i].MatchString(path) {
+				if !seen[i] {
+					seen[i] = true
+					matched = append(matched, rules[i])
+				}
+				break
+			}
+		}
+	}
+	return matched
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+)
+
+// AddComment leaves a comment on a PR. A comment with a parent is a reply
+// to the thread of the parent. Only the author and the assigned reviewers
+// of the PR can comment.
+func (s *PullRequestService) AddComment(
+	ctx context.Context,
+	dto entity.AddCommentDTO,
+) (*entity.CommentResponseDTO, error) {
+	if strings.TrimSpace(dto.Body) == "" || len(dto.Body) > 4096 {
+		return nil, errs.ErrInvalidComment
+	}
+
+	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, dto.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	if exists.Status == entity.StatusMerged {
+		return nil, errs.ErrCommentOnMergedPR
+	}
+	err = s.checkParticipant(ctx, exists, dto.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	parentId := dto.ParentId
+	if parentId != nil {
+		parent, err := s.commentRepo.GetCommentById(ctx, s.pool, *parentId)
+		if err != nil {
+			return nil, err
+		}
+		if parent.PullRequestId != exists.Id {
+			return nil, errs.ErrInvalidComment
+		}
+		if parent.ParentId != nil {
+			parentId = parent.ParentId
+		}
+	}
+
+	comment := &entity.Comment{
+		PullRequestId: exists.Id,
+		ParentId:      parentId,
+		AuthorId:      dto.UserId,
+		Body:          dto.Body,
+	}
+	err = s.commentRepo.AddComment(ctx, s.pool, comment)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.CommentResponseDTO{Comment: commentDTO(comment)}, nil
+}
+
+// GetComments returns the comment threads of a PR with their replies, both
+// in the order they were left.
+func (s *PullRequestService) GetComments(
+	ctx context.Context,
+	prId string,
+) (*entity.PullRequestCommentsDTO, error) {
+	_, err := s.prRepo.GetPullRequestById(ctx, s.pool, prId)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.commentRepo.GetCommentsByPrId(ctx, s.pool, prId)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PullRequestCommentsDTO{
+		PullRequestId: prId,
+		Threads:       commentThreads(comments),
+	}, nil
+}
+
+// ResolveComment resolves the thread of a comment. Resolving a resolved
+// thread returns it unchanged.
+func (s *PullRequestService) ResolveComment(
+	ctx context.Context,
+	dto entity.ResolveCommentDTO,
+) (*entity.CommentResponseDTO, error) {
+	comment, err := s.commentRepo.GetCommentById(ctx, s.pool, dto.CommentId)
+	if err != nil {
+		return nil, err
+	}
+	threadId := comment.Id
+	if comment.ParentId != nil {
+		threadId = *comment.ParentId
+	}
+
+	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, comment.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	if exists.Status == entity.StatusMerged {
+		return nil, errs.ErrCommentOnMergedPR
+	}
+	err = s.checkParticipant(ctx, exists, dto.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.commentRepo.ResolveComment(ctx, s.pool, threadId, dto.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.GetCommentsByPrId(ctx, s.pool, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	threads := commentThreads(comments)
+	i := slices.IndexFunc(threads, func(t entity.CommentDTO) bool {
+		return t.CommentId == threadId
+	})
+	if i < 0 {
+		return nil, errs.ErrNotFound("comment", "id", threadId)
+	}
+	return &entity.CommentResponseDTO{Comment: threads[i]}, nil
+}
+
+// checkParticipant returns ErrNotParticipant unless userId is the author
+// or an assigned reviewer of pr.
+func (s *PullRequestService) checkParticipant(
+	ctx context.Context,
+	pr *entity.PullRequest,
+	userId string,
+) error {
+	if userId == pr.AuthorId {
+		return nil
+	}
+	assigned, err := s.assignedReviewerIds(ctx, s.pool, pr.Id)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(assigned, userId) {
+		return errs.ErrNotParticipant
+	}
+	return nil
+}
+
+// commentThreads groups comments ordered by creation into threads.
+func commentThreads(comments []entity.Comment) []entity.CommentDTO {
+	threads := make([]entity.CommentDTO, 0)
+	index := make(map[int64]int)
+	for _, c := range comments {
+		if c.ParentId == nil {
+			index[c.Id] = len(threads)
+			threads = append(threads, commentDTO(&c))
+		}
+	}
+	for _, c := range comments {
+		if c.ParentId == nil {
+			continue
+		}
+		if i, ok := index[*c.ParentId]; ok {
+			threads[i].Replies = append(threads[i].Replies, commentDTO(&c))
+		}
+	}
+	return threads
+}
+
+func commentDTO(c *entity.Comment) entity.CommentDTO {
+	dto := entity.CommentDTO{
+		CommentId:  c.Id,
+		ParentId:   c.ParentId,
+		AuthorId:   c.AuthorId,
+		Body:       c.Body,
+		Resolved:   c.ResolvedAt != nil,
+		ResolvedBy: c.ResolvedBy,
+		CreatedAt:  c.CreatedAt.Format(time.RFC3339),
+	}
+	if c.ResolvedAt != nil {
+		resolvedAt := c.ResolvedAt.Format(time.RFC3339)
+		dto.ResolvedAt = &resolvedAt
+	}
+	return dto
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+// GetPullRequestHistory returns status and reviewer changes of a PR in the
+// order they happened.
+func (s *PullRequestService) GetPullRequestHistory(
+	ctx context.Context,
+	prId string,
+) (*entity.PullRequestHistoryDTO, error) {
+	_, err := s.prRepo.GetPullRequestById(ctx, s.pool, prId)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.prRepo.GetPullRequestEvents(ctx, s.pool, prId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.PullRequestHistoryDTO{
+		PullRequestId: prId,
+		Events:        make([]entity.PullRequestEventDTO, len(events)),
+	}
+	for i, e := range events {
+		result.Events[i] = entity.PullRequestEventDTO{
+			EventId:    e.Id,
+			Kind:       e.Kind,
+			ActorId:    e.ActorId,
+			UserId:     e.UserId,
+			FromStatus: e.FromStatus,
+			ToStatus:   e.ToStatus,
+			Fields:     e.Fields,
+			CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return result, nil
+}
+
+// actor checks that the user acting on a PR exists. An empty actorId is
+// allowed for clients that don't identify their users and gives nil.
+func (s *PullRequestService) actor(
+	ctx context.Context,
+	db repository.Querier,
+	actorId string,
+) (*string, error) {
+	if actorId == "" {
+		return nil, nil
+	}
+	_, err := s.userRepo.GetById(ctx, db, actorId)
+	if err != nil {
+		return nil, err
+	}
+	return &actorId, nil
+}
+
+// statusBeforeClose returns the status prId had before it was last closed,
+// StatusOpen if its history doesn't record the close.
+func (s *PullRequestService) statusBeforeClose(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) (string, error) {
+	events, err := s.prRepo.GetPullRequestEvents(ctx, db, prId)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range slices.Backward(events) {
+		if e.Kind == entity.EventStatusChanged && e.FromStatus != nil &&
+			e.ToStatus != nil && *e.ToStatus == entity.StatusClosed {
+			return *e.FromStatus, nil
+		}
+	}
+	return entity.StatusOpen, nil
+}
+
+// recordStatusChange stores the move of a PR from status from to status
+// to. from is empty for a newly created PR.
+func (s *PullRequestService) recordStatusChange(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	actorId *string,
+	from string,
+	to string,
+) error {
+	event := &entity.PullRequestEvent{
+		PullRequestId: prId,
+		Kind:          entity.EventStatusChanged,
+		ActorId:       actorId,
+		ToStatus:      &to,
+	}
+	if from != "" {
+		event.FromStatus = &from
+	}
+	return s.prRepo.AddPullRequestEvent(ctx, db, event)
+}
+
+// recordReviewerChanges stores an event of kind for each of userIds.
+func (s *PullRequestService) recordReviewerChanges(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	actorId *string,
+	kind string,
+	userIds ...string,
+) error {
+	for _, userId := range userIds {
+		err := s.prRepo.AddPullRequestEvent(ctx, db, &entity.PullRequestEvent{
+			PullRequestId: prId,
+			Kind:          kind,
+			ActorId:       actorId,
+			UserId:        &userId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,30 +4,127 @@ import (
 	"context"
 
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
 )
 
 type BaseUserService interface {
-	SetIsActive(ctx context.Context, dto entity.SetUserIsActiveDTO) (*entity.UserDTO, error)
+	SetIsActive(
+		ctx context.Context,
+		dto entity.SetUserIsActiveDTO,
+	) (*entity.SetUserIsActiveResponseDTO, error)
+	SetSkills(ctx context.Context, dto entity.SetUserSkillsDTO) (*entity.UserDTO, error)
+	SetMaxOpenReviews(
+		ctx context.Context,
+		dto entity.SetUserMaxOpenReviewsDTO,
+	) (*entity.UserDTO, error)
+	AddAbsence(ctx context.Context, dto entity.AbsenceDTO) (*entity.AbsenceDTO, error)
+	GetAbsences(ctx context.Context, userId string) (*entity.UserAbsencesDTO, error)
+	CancelAbsence(ctx context.Context, dto entity.CancelAbsenceDTO) (*entity.UserAbsencesDTO, error)
 	GetReview(ctx context.Context, userId string) (*entity.UserPullRequestsDTO, error)
 }
 
 type BaseTeamService interface {
 	AddTeam(ctx context.Context, dto entity.TeamDTO) (*entity.ResponseTeamDTO, error)
 	GetTeam(ctx context.Context, teamName string) (*entity.TeamDTO, error)
+	SetTeamSettings(ctx context.Context, dto entity.TeamSettingsDTO) (*entity.TeamDTO, error)
+	SetFallbackTeams(ctx context.Context, dto entity.SetFallbackTeamsDTO) (*entity.TeamDTO, error)
+	GetCodeOwners(ctx context.Context, teamName string) (*entity.TeamCodeOwnersDTO, error)
+	SetCodeOwners(
+		ctx context.Context,
+		dto entity.TeamCodeOwnersDTO,
+	) (*entity.TeamCodeOwnersDTO, error)
+	GetRepositories(ctx context.Context, teamName string) (*entity.TeamRepositoriesDTO, error)
+	SetRepository(ctx context.Context, dto entity.RepositoryDTO) (*entity.RepositoryDTO, error)
+}
+
+type BaseReviewerRuleService interface {
+	GetRules(ctx context.Context, authorId string) (*entity.AuthorReviewerRulesDTO, error)
+	SetRule(ctx context.Context, dto entity.ReviewerRuleDTO) (*entity.AuthorReviewerRulesDTO, error)
+	RemoveRule(
+		ctx context.Context,
+		dto entity.RemoveReviewerRuleDTO,
+	) (*entity.AuthorReviewerRulesDTO, error)
 }
 
 type BasePullRequestService interface {
 	GetOpenPullRequestsByReviewers(ctx context.Context) ([]entity.UserStatsDTO, error)
+	GetPullRequest(ctx context.Context, prId string) (*entity.PullRequestResponseDTO, error)
+	GetPullRequestByNumber(
+		ctx context.Context,
+		repositoryName string,
+		number int,
+	) (*entity.PullRequestResponseDTO, error)
+	ListPullRequests(
+		ctx context.Context,
+		dto entity.ListPullRequestsDTO,
+	) (*entity.PullRequestListDTO, error)
 	CreatePullRequest(
 		ctx context.Context,
 		dto entity.PullRequestCreateDTO,
 	) (*entity.PullRequestResponseDTO, error)
+	PreviewAssignment(
+		ctx context.Context,
+		dto entity.AssignmentPreviewRequestDTO,
+	) (*entity.AssignmentPreviewDTO, error)
 	MergePullRequest(
 		ctx context.Context,
 		dto entity.MergePullRequestDTO,
 	) (*entity.PullRequestResponseDTO, error)
+	UpdatePullRequest(
+		ctx context.Context,
+		dto entity.UpdatePullRequestDTO,
+	) (*entity.PullRequestResponseDTO, error)
+	AddComment(
+		ctx context.Context,
+		dto entity.AddCommentDTO,
+	) (*entity.CommentResponseDTO, error)
+	GetComments(ctx context.Context, prId string) (*entity.PullRequestCommentsDTO, error)
+	ResolveComment(
+		ctx context.Context,
+		dto entity.ResolveCommentDTO,
+	) (*entity.CommentResponseDTO, error)
+	SubmitReview(
+		ctx context.Context,
+		dto entity.SubmitReviewDTO,
+	) (*entity.PullRequestResponseDTO, error)
+	MarkReadyPullRequest(
+		ctx context.Context,
+		dto entity.MarkReadyPullRequestDTO,
+	) (*entity.PullRequestResponseDTO, error)
+	ClosePullRequest(
+		ctx context.Context,
+		dto entity.ClosePullRequestDTO,
+	) (*entity.PullRequestResponseDTO, error)
+	ReopenPullRequest(
+		ctx context.Context,
+		dto entity.ReopenPullRequestDTO,
+	) (*entity.PullRequestResponseDTO, error)
 	ReassignPullRequest(
 		ctx context.Context,
 		dto entity.ReassignPullRequestDTO,
 	) (*entity.PullRequestResponseDTO, error)
+	AddReviewer(
+		ctx context.Context,
+		dto entity.PullRequestReviewerDTO,
+	) (*entity.PullRequestResponseDTO, error)
+	RemoveReviewer(
+		ctx context.Context,
+		dto entity.PullRequestReviewerDTO,
+	) (*entity.PullRequestResponseDTO, error)
+	RebalanceTeam(
+		ctx context.Context,
+		dto entity.RebalanceTeamDTO,
+	) (*entity.RebalanceTeamResultDTO, error)
+	ReassignUserReviews(
+		ctx context.Context,
+		db repository.Querier,
+		userId string,
+	) ([]entity.AffectedPullRequestDTO, error)
+	GetPullRequestHistory(ctx context.Context, prId string) (*entity.PullRequestHistoryDTO, error)
+	GetAssignments(ctx context.Context, prId string) (*entity.PullRequestAssignmentsDTO, error)
+	ReplayAssignment(
+		ctx context.Context,
+		prId string,
+		assignmentId int64,
+	) (*entity.AssignmentReplayDTO, error)
 }
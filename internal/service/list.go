@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+func (s *PullRequestService) GetPullRequest(
+	ctx context.Context,
+	prId string,
+) (*entity.PullRequestResponseDTO, error) {
+	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, prId)
+	if err != nil {
+		return nil, err
+	}
+	prDTO, err := s.pullRequestDTO(ctx, s.pool, exists)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+}
+
+// ListPullRequests returns a page of PRs matching the filters of dto. The
+// next page is requested with the returned cursor and the same filters,
+// NextCursor is nil on the last page.
+func (s *PullRequestService) ListPullRequests(
+	ctx context.Context,
+	dto entity.ListPullRequestsDTO,
+) (*entity.PullRequestListDTO, error) {
+	filter, err := pullRequestFilter(dto)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	filter.Limit++
+
+	prs, err := s.prRepo.ListPullRequests(ctx, s.pool, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.PullRequestListDTO{
+		PullRequests: make([]entity.PullRequestDTO, 0, min(len(prs), limit)),
+	}
+	if len(prs) > limit {
+		prs = prs[:limit]
+		last := prs[limit-1]
+		cursor := encodeCursor(entity.PullRequestCursor{
+			CreatedAt:       last.CreatedAt,
+			PullRequestName: last.PullRequestName,
+			Id:              last.Id,
+			SortBy:          filter.SortBy,
+			Order:           dto.Order,
+		})
+		result.NextCursor = &cursor
+	}
+	prDTOs, err := s.pullRequestDTOs(ctx, s.pool, prs)
+	if err != nil {
+		return nil, err
+	}
+	result.PullRequests = append(result.PullRequests, prDTOs...)
+	return result, nil
+}
+
+// pullRequestDTOs builds the views of prs like pullRequestDTO, loading
+// reviewers, labels, reviews and unresolved threads of all of them at once.
+// Dependencies are loaded only for stacked PRs.
+func (s *PullRequestService) pullRequestDTOs(
+	ctx context.Context,
+	db repository.Querier,
+	prs []entity.PullRequest,
+) ([]entity.PullRequestDTO, error) {
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	prIds := make([]string, len(prs))
+	for i, pr := range prs {
+		prIds[i] = pr.Id
+	}
+
+	reviewers, err := s.prRepo.GetReviewersByPrIds(ctx, db, prIds)
+	if err != nil {
+		return nil, err
+	}
+	assigned := make(map[string][]string)
+	for _, r := range reviewers {
+		assigned[r.PullRequestId] = append(assigned[r.PullRequestId], r.UserId)
+	}
+	prLabels, err := s.prRepo.GetLabelsByPrIds(ctx, db, prIds)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string][]string)
+	for _, l := range prLabels {
+		labels[l.PullRequestId] = append(labels[l.PullRequestId], l.Label)
+	}
+	prReviews, err := s.reviewRepo.GetReviewsByPrIds(ctx, db, prIds)
+	if err != nil {
+		return nil, err
+	}
+	reviews := make(map[string][]entity.Review)
+	for _, r := range prReviews {
+		reviews[r.PullRequestId] = append(reviews[r.PullRequestId], r)
+	}
+	unresolved, err := s.commentRepo.CountUnresolvedThreadsByPrIds(ctx, db, prIds)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.PullRequestDTO, len(prs))
+	for i, pr := range prs {
+		var dependencies []entity.PullRequestDependencyDTO
+		if pr.ParentId != nil {
+			dependencies, err = s.dependencies(ctx, db, pr.Id)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result[i] = *newPullRequestDTO(
+			&pr,
+			assigned[pr.Id],
+			labels[pr.Id],
+			reviews[pr.Id],
+			unresolved[pr.Id],
+			dependencies,
+		)
+	}
+	return result, nil
+}
+
+// pullRequestFilter validates dto and fills in the default sort and limit.
+func pullRequestFilter(dto entity.ListPullRequestsDTO) (*entity.PullRequestFilter, error) {
+	filter := &entity.PullRequestFilter{
+		CreatedFrom: dto.CreatedFrom,
+		CreatedTo:   dto.CreatedTo,
+		MergedFrom:  dto.MergedFrom,
+		MergedTo:    dto.MergedTo,
+		SortBy:      dto.SortBy,
+		Limit:       dto.Limit,
+	}
+	if dto.Status != "" {
+		statuses := []string{
+			entity.StatusOpen,
+			entity.StatusMerged,
+			entity.StatusClosed,
+			entity.StatusDraft,
+		}
+		if !slices.Contains(statuses, dto.Status) {
+			return nil, errs.ErrBadFilter("unknown status")
+		}
+		filter.Status = &dto.Status
+	}
+	if dto.AuthorId != "" {
+		filter.AuthorId = &dto.AuthorId
+	}
+	if dto.TeamName != "" {
+		filter.TeamName = &dto.TeamName
+	}
+	if dto.RepositoryName != "" {
+		filter.RepositoryName = &dto.RepositoryName
+	}
+	if dto.ReviewerId != "" {
+		filter.ReviewerId = &dto.ReviewerId
+	}
+	if dto.Label != "" {
+		label := strings.ToLower(strings.TrimSpace(dto.Label))
+		filter.Label = &label
+	}
+	if !validRange(dto.CreatedFrom, dto.CreatedTo) || !validRange(dto.MergedFrom, dto.MergedTo) {
+		return nil, errs.ErrBadFilter("range start is after its end")
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = entity.SortCreatedAt
+	}
+	if filter.SortBy != entity.SortCreatedAt && filter.SortBy != entity.SortName {
+		return nil, errs.ErrBadFilter("unknown sort")
+	}
+	if dto.Order == "" {
+		dto.Order = entity.OrderDesc
+		if filter.SortBy == entity.SortName {
+			dto.Order = entity.OrderAsc
+		}
+	}
+	if dto.Order != entity.OrderAsc && dto.Order != entity.OrderDesc {
+		return nil, errs.ErrBadFilter("unknown order")
+	}
+	filter.Descending = dto.Order == entity.OrderDesc
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxListLimit {
+		return nil, errs.ErrBadFilter("limit is out of range")
+	}
+
+	if dto.Cursor != "" {
+		cursor, err := decodeCursor(dto.Cursor)
+		if err != nil || cursor.SortBy != filter.SortBy || cursor.Order != dto.Order {
+			return nil, errs.ErrBadFilter("invalid cursor")
+		}
+		filter.After = cursor
+	}
+	return filter, nil
+}
+
+func validRange(from *time.Time, to *time.Time) bool {
+	return from == nil || to == nil || !from.After(*to)
+}
+
+// encodeCursor makes the opaque cursor pointing after a PR. The cursor
+// remembers the sort it was made for, so it can't be used with another one.
+func encodeCursor(cursor entity.PullRequestCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (*entity.PullRequestCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	var res entity.PullRequestCursor
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
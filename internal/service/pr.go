@@ -5,6 +5,9 @@ import (
 	"errors"
 	"log/slog"
 	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
@@ -15,10 +18,20 @@ import (
 )
 
 type PullRequestService struct {
-	logger   *slog.Logger
-	pool     *pgxpool.Pool
-	prRepo   repository.BasePullRequestRepository
-	userRepo repository.BaseUserRepository
+	logger         *slog.Logger
+	pool           *pgxpool.Pool
+	prRepo         repository.BasePullRequestRepository
+	userRepo       repository.BaseUserRepository
+	teamRepo       repository.BaseTeamRepository
+	ruleRepo       repository.BaseReviewerRuleRepository
+	assignmentRepo repository.BaseAssignmentRepository
+	reviewRepo     repository.BaseReviewRepository
+	commentRepo    repository.BaseCommentRepository
+	selectors      map[string]ReviewerSelector
+
+	// mu guards source.
+	mu     sync.Mutex
+	source rand.Source
 }
 
 func NewPullRequestService(
@@ -26,13 +39,39 @@ func NewPullRequestService(
 	pool *pgxpool.Pool,
 	prRepo repository.BasePullRequestRepository,
 	userRepo repository.BaseUserRepository,
+	teamRepo repository.BaseTeamRepository,
+	ruleRepo repository.BaseReviewerRuleRepository,
+	assignmentRepo repository.BaseAssignmentRepository,
+	reviewRepo repository.BaseReviewRepository,
+	commentRepo repository.BaseCommentRepository,
+	source rand.Source,
 ) BasePullRequestService {
 	logger := baseLogger.With("module", "prservice")
+
+	selectors := make(map[string]ReviewerSelector)
+	for _, strategy := range []string{
+		entity.StrategyRandom,
+		entity.StrategyRoundRobin,
+		entity.StrategyLeastLoaded,
+		entity.StrategyWeighted,
+		entity.StrategyRecentHistory,
+	} {
+		selector, _ := NewReviewerSelector(strategy)
+		selectors[strategy] = selector
+	}
+
 	return &PullRequestService{
-		logger:   logger,
-		pool:     pool,
-		prRepo:   prRepo,
-		userRepo: userRepo,
+		logger:         logger,
+		pool:           pool,
+		prRepo:         prRepo,
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		ruleRepo:       ruleRepo,
+		assignmentRepo: assignmentRepo,
+		reviewRepo:     reviewRepo,
+		commentRepo:    commentRepo,
+		selectors:      selectors,
+		source:         source,
 	}
 }
 
@@ -57,6 +96,15 @@ func (s *PullRequestService) CreatePullRequest(
 	ctx context.Context,
 	dto entity.PullRequestCreateDTO,
 ) (*entity.PullRequestResponseDTO, error) {
+	if dto.RepositoryName != nil || dto.Number != nil {
+		prId, err := s.repositoryPullRequestId(ctx, dto)
+		if err != nil {
+			return nil, err
+		}
+		dto.PullRequestId = prId
+	} else if strings.Contains(dto.PullRequestId, "#") {
+		return nil, errs.ErrInvalidPullRequestId
+	}
 	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, dto.PullRequestId)
 	if err != nil && !errors.Is(err, errs.ErrBaseNotFound) {
 		return nil, err
@@ -64,6 +112,37 @@ func (s *PullRequestService) CreatePullRequest(
 	if exists != nil {
 		return nil, errs.ErrPullRequestAlreadyExists
 	}
+	err = validatePullRequestFields(dto.PullRequestName, dto.Description)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range dto.ChangedPaths {
+		if path == "" || len(path) > 1024 {
+			return nil, errs.ErrInvalidChangedPath
+		}
+	}
+	labels, err := normalizeTags(dto.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.userRepo.GetById(ctx, s.pool, dto.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+	if dto.ParentId != nil && *dto.ParentId == "" {
+		dto.ParentId = nil
+	}
+	if dto.ParentId != nil {
+		err = s.checkParent(ctx, s.pool, dto.PullRequestId, *dto.ParentId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	status := entity.StatusOpen
+	if dto.Draft {
+		status = entity.StatusDraft
+	}
 
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -71,129 +150,227 @@ func (s *PullRequestService) CreatePullRequest(
 	}
 	defer tx.Rollback(ctx)
 
-	err = s.prRepo.AddPullRequest(ctx, tx, &entity.PullRequest{
+	pr := &entity.PullRequest{
 		Id:              dto.PullRequestId,
 		PullRequestName: dto.PullRequestName,
+		Description:     dto.Description,
 		AuthorId:        dto.AuthorId,
-		Status:          entity.StatusOpen,
-	})
-	if err != nil {
-		return nil, err
+		Status:          status,
+		ParentId:        dto.ParentId,
+		RepositoryName:  dto.RepositoryName,
+		Number:          dto.Number,
 	}
-
-	author, err := s.userRepo.GetById(ctx, tx, dto.AuthorId)
+	err = s.prRepo.AddPullRequest(ctx, tx, pr)
 	if err != nil {
 		return nil, err
 	}
-
-	availableUsers, err := s.userRepo.GetActiveByTeamName(ctx, tx, author.TeamName)
+	err = s.recordStatusChange(ctx, tx, pr.Id, &pr.AuthorId, "", status)
 	if err != nil {
 		return nil, err
 	}
-
-	assigned := make([]string, 0, 2)
-	validCount := 0
-	for _, user := range availableUsers {
-		if user.Id == dto.AuthorId {
-			continue
-		}
-
-		validCount++
-
-		if len(assigned) < 2 {
-			assigned = append(assigned, user.Id)
-		} else {
-			j := rand.IntN(validCount)
-			if j < 2 {
-				assigned[j] = user.Id
-			}
+	if len(dto.ChangedPaths) > 0 {
+		err = s.prRepo.AddPullRequestPaths(ctx, tx, dto.PullRequestId, dto.ChangedPaths)
+		if err != nil {
+			return nil, err
 		}
 	}
-
-	for _, aId := range assigned {
-		err := s.prRepo.AddReviewerToPullRequest(ctx, tx, dto.PullRequestId, aId)
+	if len(labels) > 0 {
+		err = s.prRepo.AddPullRequestLabels(ctx, tx, dto.PullRequestId, labels)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	err = tx.Commit(ctx)
+	dependencies, err := s.dependencies(ctx, tx, pr.Id)
 	if err != nil {
-		return nil, errs.ErrInternal("error commit transaction", err)
+		return nil, err
 	}
 
-	return &entity.PullRequestResponseDTO{
+	createdAt := pr.CreatedAt.Format(time.RFC3339)
+	res := &entity.PullRequestResponseDTO{
 		PullRequest: entity.PullRequestDTO{
 			PullRequestId:     dto.PullRequestId,
 			PullRequestName:   dto.PullRequestName,
+			Description:       dto.Description,
 			AuthorId:          dto.AuthorId,
-			Status:            entity.StatusOpen,
-			AssignedReviewers: assigned,
+			Status:            status,
+			AssignedReviewers: []string{},
+			Labels:            labels,
+			CreatedAt:         &createdAt,
+			ParentId:          pr.ParentId,
+			Dependencies:      dependencies,
+			RepositoryName:    pr.RepositoryName,
+			Number:            pr.Number,
 		},
-	}, nil
+	}
+	if !dto.Draft {
+		res, err = s.assignInitialReviewers(ctx, tx, pr, &pr.AuthorId, dto.ChangedPaths, labels)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+
+	return res, nil
 }
 
-func (s *PullRequestService) MergePullRequest(
+// UpdatePullRequest changes the name, description and labels of a PR that
+// isn't merged and records the changed fields in its history. Values equal
+// to the current ones are not counted as changes.
+func (s *PullRequestService) UpdatePullRequest(
 	ctx context.Context,
-	dto entity.MergePullRequestDTO,
+	dto entity.UpdatePullRequestDTO,
 ) (*entity.PullRequestResponseDTO, error) {
+	if dto.PullRequestName == nil && dto.Description == nil && dto.Labels == nil &&
+		dto.ParentId == nil {
+		return nil, errs.ErrEmptyPullRequestUpdate
+	}
+
 	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, dto.PullRequestId)
 	if err != nil {
 		return nil, err
 	}
+	if exists.Status == entity.StatusMerged {
+		return nil, errs.ErrUpdateMergedPR
+	}
 
-	assigned, err := s.userRepo.GetReviewersByPrId(ctx, s.pool, dto.PullRequestId)
+	update := &entity.PullRequestUpdate{}
+	var fields []string
+	if dto.PullRequestName != nil && *dto.PullRequestName != exists.PullRequestName {
+		update.PullRequestName = dto.PullRequestName
+		exists.PullRequestName = *dto.PullRequestName
+		fields = append(fields, entity.FieldName)
+	}
+	if dto.Description != nil && *dto.Description != exists.Description {
+		update.Description = dto.Description
+		exists.Description = *dto.Description
+		fields = append(fields, entity.FieldDescription)
+	}
+	err = validatePullRequestFields(exists.PullRequestName, exists.Description)
 	if err != nil {
 		return nil, err
 	}
-	assignedIds := make([]string, len(assigned))
-	for i, u := range assigned {
-		assignedIds[i] = u.Id
+	currentParent := ""
+	if exists.ParentId != nil {
+		currentParent = *exists.ParentId
+	}
+	if dto.ParentId != nil && *dto.ParentId != currentParent {
+		if *dto.ParentId != "" {
+			err = s.checkParent(ctx, s.pool, exists.Id, *dto.ParentId)
+			if err != nil {
+				return nil, err
+			}
+			exists.ParentId = dto.ParentId
+		} else {
+			exists.ParentId = nil
+		}
+		update.ParentId = dto.ParentId
+		fields = append(fields, entity.FieldParent)
 	}
 
-	if exists.Status == entity.StatusMerged {
-		fmtTime := exists.UpdatedAt.Format(time.RFC3339)
+	var labels []string
+	if dto.Labels != nil {
+		labels, err = normalizeTags(*dto.Labels)
+		if err != nil {
+			return nil, err
+		}
+		current, err := s.prRepo.GetPullRequestLabels(ctx, s.pool, exists.Id)
+		if err != nil {
+			return nil, err
+		}
+		slices.Sort(current)
+		if !slices.Equal(slices.Sorted(slices.Values(labels)), current) {
+			fields = append(fields, entity.FieldLabels)
+		}
+	}
+
+	actorId, err := s.actor(ctx, s.pool, dto.ActorId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
 
-		return &entity.PullRequestResponseDTO{
-			PullRequest: entity.PullRequestDTO{
-				PullRequestId:     exists.Id,
-				PullRequestName:   exists.PullRequestName,
-				AuthorId:          exists.AuthorId,
-				Status:            exists.Status,
-				AssignedReviewers: assignedIds,
-				MergedAt:          &fmtTime,
-			},
-		}, nil
+	if update.PullRequestName != nil || update.Description != nil || update.ParentId != nil {
+		err = s.prRepo.UpdatePullRequest(ctx, tx, exists.Id, update)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if slices.Contains(fields, entity.FieldLabels) {
+		err = s.prRepo.SetPullRequestLabels(ctx, tx, exists.Id, labels)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(fields) > 0 {
+		err = s.prRepo.AddPullRequestEvent(ctx, tx, &entity.PullRequestEvent{
+			PullRequestId: exists.Id,
+			Kind:          entity.EventUpdated,
+			ActorId:       actorId,
+			Fields:        fields,
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	currTimeAsStr := time.Now().Format(time.RFC3339)
-	err = s.prRepo.UpdatePullRequestStatus(ctx, s.pool, exists.Id, entity.StatusMerged)
+	prDTO, err := s.pullRequestDTO(ctx, tx, exists)
 	if err != nil {
 		return nil, err
 	}
 
-	return &entity.PullRequestResponseDTO{
-		PullRequest: entity.PullRequestDTO{
-			PullRequestId:     exists.Id,
-			PullRequestName:   exists.PullRequestName,
-			AuthorId:          exists.AuthorId,
-			Status:            entity.StatusMerged,
-			AssignedReviewers: assignedIds,
-			MergedAt:          &currTimeAsStr,
-		},
-	}, err
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+	return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
 }
 
-func (s *PullRequestService) ReassignPullRequest(
+// validatePullRequestFields checks name and description against the
+// limits of the pull_requests columns.
+func validatePullRequestFields(name string, description string) error {
+	if strings.TrimSpace(name) == "" || len(name) > 128 {
+		return errs.ErrInvalidPullRequestName
+	}
+	if len(description) > 4096 {
+		return errs.ErrInvalidDescription
+	}
+	return nil
+}
+
+// MarkReadyPullRequest opens a draft PR and assigns its reviewers the same
+// way CreatePullRequest does for ready PRs. Repeated calls return the PR
+// unchanged.
+func (s *PullRequestService) MarkReadyPullRequest(
 	ctx context.Context,
-	dto entity.ReassignPullRequestDTO,
+	dto entity.MarkReadyPullRequestDTO,
 ) (*entity.PullRequestResponseDTO, error) {
 	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, dto.PullRequestId)
 	if err != nil {
 		return nil, err
 	}
-	if exists.Status == entity.StatusMerged {
-		return nil, errs.ErrReassignOnMergedPR
+	if exists.Status == entity.StatusOpen {
+		prDTO, err := s.pullRequestDTO(ctx, s.pool, exists)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+	}
+	if exists.Status != entity.StatusDraft {
+		return nil, errs.ErrNotDraftPR
+	}
+	actorId, err := s.actor(ctx, s.pool, dto.ActorId)
+	if err != nil {
+		return nil, err
 	}
 
 	tx, err := s.pool.Begin(ctx)
@@ -202,55 +379,270 @@ func (s *PullRequestService) ReassignPullRequest(
 	}
 	defer tx.Rollback(ctx)
 
-	err = s.prRepo.RemoveReviewerFromPullRequest(ctx, tx, exists.Id, dto.OldReviewerId)
+	paths, err := s.prRepo.GetPullRequestPaths(ctx, tx, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.prRepo.GetPullRequestLabels(ctx, tx, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.prRepo.UpdatePullRequestStatus(ctx, tx, exists.Id, entity.StatusOpen)
+	if err != nil {
+		return nil, err
+	}
+	err = s.recordStatusChange(ctx, tx, exists.Id, actorId, exists.Status, entity.StatusOpen)
+	if err != nil {
+		return nil, err
+	}
+	exists.Status = entity.StatusOpen
+
+	res, err := s.assignInitialReviewers(ctx, tx, exists, actorId, paths, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+
+	return res, nil
+}
+
+// assignInitialReviewers selects and stores the first reviewers of an open
+// PR inside tx.
+func (s *PullRequestService) assignInitialReviewers(
+	ctx context.Context,
+	tx repository.Querier,
+	pr *entity.PullRequest,
+	actorId *string,
+	paths []string,
+	labels []string,
+) (*entity.PullRequestResponseDTO, error) {
+	plan, err := s.planAssignment(ctx, tx, s.nextSeed(), pr.AuthorId, pr.RepositoryName, paths, labels)
 	if err != nil {
-		if errors.Is(err, errs.ErrBaseNotFound) {
-			return nil, errs.ErrUserNotAssigned
+		return nil, err
+	}
+	selected := plan.selected
+	if len(selected) < plan.team.MinReviewers {
+		return nil, plan.req.shortage(errs.ErrNotEnoughReviewers)
+	}
+	assigned := selectedIds(selected)
+
+	for _, aId := range assigned {
+		err := s.prRepo.AddReviewerToPullRequest(ctx, tx, pr.Id, aId)
+		if err != nil {
+			return nil, err
 		}
+	}
+	err = s.recordReviewerChanges(ctx, tx, pr.Id, actorId, entity.EventReviewerAdded, assigned...)
+	if err != nil {
 		return nil, err
 	}
 
-	oldReviewer, err := s.userRepo.GetById(ctx, tx, dto.OldReviewerId)
+	assignmentId, err := s.saveAssignment(
+		ctx,
+		tx,
+		pr.Id,
+		entity.AssignmentCreate,
+		plan.req,
+		assigned,
+	)
+	if err != nil {
+		return nil, err
+	}
+	unresolved, err := s.commentRepo.CountUnresolvedThreads(ctx, tx, pr.Id)
 	if err != nil {
 		return nil, err
 	}
-	activeUsers, err := s.userRepo.GetActiveByTeamName(ctx, tx, oldReviewer.TeamName)
+	dependencies, err := s.dependencies(ctx, tx, pr.Id)
 	if err != nil {
 		return nil, err
 	}
-	if len(activeUsers) == 0 {
-		return nil, errs.ErrNoActiveUsers
+
+	createdAt := pr.CreatedAt.Format(time.RFC3339)
+	return &entity.PullRequestResponseDTO{
+		PullRequest: entity.PullRequestDTO{
+			PullRequestId:     pr.Id,
+			PullRequestName:   pr.PullRequestName,
+			Description:       pr.Description,
+			AuthorId:          pr.AuthorId,
+			Status:            pr.Status,
+			AssignedReviewers: assigned,
+			Labels:            labels,
+			CreatedAt:         &createdAt,
+			UnresolvedThreads: unresolved,
+			ParentId:          pr.ParentId,
+			Dependencies:      dependencies,
+			RepositoryName:    pr.RepositoryName,
+			Number:            pr.Number,
+		},
+		FallbackReviewers: fallbackReviewers(selected, plan.team.TeamName),
+		AssignmentId:      &assignmentId,
+		Assignment:        s.explainAssignment(plan.team, plan.req, selected),
+	}, nil
+}
+
+// PreviewAssignment runs the reviewer selection of CreatePullRequest for a
+// PR of dto.AuthorId without storing anything and reports the candidate
+// pool of every considered team.
+func (s *PullRequestService) PreviewAssignment(
+	ctx context.Context,
+	dto entity.AssignmentPreviewRequestDTO,
+) (*entity.AssignmentPreviewDTO, error) {
+	for _, path := range dto.ChangedPaths {
+		if path == "" || len(path) > 1024 {
+			return nil, errs.ErrInvalidChangedPath
+		}
+	}
+	labels, err := normalizeTags(dto.Labels)
+	if err != nil {
+		return nil, err
 	}
 
-	var newAssignedIdPtr string
-	for range len(activeUsers) {
-		rndId := rand.IntN(len(activeUsers))
-		currId := activeUsers[rndId].Id
+	// The preview is never stored, so its seed is drawn apart from the
+	// shared source to keep the seeds of real assignments unaffected.
+	plan, err := s.planAssignment(
+		ctx,
+		s.pool,
+		rand.Uint64(),
+		dto.AuthorId,
+		dto.RepositoryName,
+		dto.ChangedPaths,
+		labels,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		if currId == dto.OldReviewerId && len(activeUsers) == 1 {
-			return nil, errs.ErrNoActiveUsers
+	result := &entity.AssignmentPreviewDTO{
+		AuthorId:          dto.AuthorId,
+		TeamName:          plan.team.TeamName,
+		MinReviewers:      plan.team.MinReviewers,
+		MaxReviewers:      plan.team.MaxReviewers,
+		Eligible:          []entity.PreviewCandidateDTO{},
+		Excluded:          []entity.ExcludedCandidateDTO{},
+		ProposedReviewers: selectedIds(plan.selected),
+		FallbackReviewers: fallbackReviewers(plan.selected, plan.team.TeamName),
+		Steps:             plan.req.steps,
+	}
+	for _, team := range plan.teams {
+		members, err := s.userRepo.GetByTeamName(ctx, s.pool, team.TeamName)
+		if err != nil {
+			return nil, err
 		}
-		if currId != dto.OldReviewerId && currId != exists.AuthorId {
-			err := s.prRepo.AddReviewerToPullRequest(ctx, tx, dto.PullRequestId, currId)
-			if err != nil {
-				return nil, err
+		available, err := s.prRepo.GetOpenPullRequestsByTeamMembers(ctx, s.pool, team.TeamName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, member := range members {
+			idx := slices.IndexFunc(available, func(a entity.UserStats) bool {
+				return a.Id == member.Id
+			})
+			// Members of teams the selection did not reach are checked
+			// against the same request, so they are reported as it would
+			// have treated them.
+			stats := entity.UserStats{Id: member.Id}
+			if idx >= 0 {
+				stats = available[idx]
+			}
+			plan.req.exclude(stats)
+
+			reason := plan.req.skippedReason(member.Id)
+			switch {
+			case reason != "":
+			case !member.IsActive:
+				reason = entity.ExcludedInactive
+			case idx < 0:
+				reason = entity.ExcludedAbsent
+			}
+
+			if reason != "" {
+				result.Excluded = append(result.Excluded, entity.ExcludedCandidateDTO{
+					UserId:   member.Id,
+					TeamName: team.TeamName,
+					Reason:   reason,
+				})
+				continue
 			}
-			newAssignedIdPtr = currId
-			break
+			result.Eligible = append(result.Eligible, entity.PreviewCandidateDTO{
+				UserId:         member.Id,
+				TeamName:       team.TeamName,
+				OpenReviews:    available[idx].OpenPullRequestsCount,
+				RecentReviews:  available[idx].RecentReviewsCount,
+				MaxOpenReviews: available[idx].MaxOpenReviews,
+			})
 		}
 	}
+	return result, nil
+}
 
-	if newAssignedIdPtr == "" {
-		return nil, errs.ErrNoActiveUsers
+// MergePullRequest merges an open PR once checkMergeable allows it. The PR
+// is locked while it and its stacked parents are checked, so concurrent
+// merges and status changes see the result of each other.
+func (s *PullRequestService) MergePullRequest(
+	ctx context.Context,
+	dto entity.MergePullRequestDTO,
+) (*entity.PullRequestResponseDTO, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
 	}
+	defer tx.Rollback(ctx)
 
-	assigned, err := s.userRepo.GetReviewersByPrId(ctx, tx, dto.PullRequestId)
+	exists, err := s.prRepo.GetPullRequestByIdForUpdate(ctx, tx, dto.PullRequestId)
 	if err != nil {
 		return nil, err
 	}
-	assignedIds := make([]string, len(assigned))
-	for i, u := range assigned {
-		assignedIds[i] = u.Id
+
+	if exists.Status == entity.StatusMerged {
+		prDTO, err := s.pullRequestDTO(ctx, tx, exists)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+	}
+
+	if exists.Status == entity.StatusClosed {
+		return nil, errs.ErrMergeClosedPR
+	}
+	if exists.Status == entity.StatusDraft {
+		return nil, errs.ErrDraftPR
+	}
+	err = s.checkAncestorsMerged(ctx, tx, exists)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkMergeable(ctx, tx, exists)
+	if err != nil {
+		return nil, err
+	}
+	actorId, err := s.actor(ctx, tx, dto.ActorId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.prRepo.UpdatePullRequestStatus(ctx, tx, exists.Id, entity.StatusMerged)
+	if err != nil {
+		return nil, err
+	}
+	err = s.prRepo.AddCompletedReviews(ctx, tx, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.recordStatusChange(ctx, tx, exists.Id, actorId, exists.Status, entity.StatusMerged)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := s.prRepo.GetPullRequestById(ctx, tx, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	prDTO, err := s.pullRequestDTO(ctx, tx, merged)
+	if err != nil {
+		return nil, err
 	}
 
 	err = tx.Commit(ctx)
@@ -258,14 +650,764 @@ func (s *PullRequestService) ReassignPullRequest(
 		return nil, errs.ErrInternal("error commit transaction", err)
 	}
 
-	return &entity.PullRequestResponseDTO{
-		PullRequest: entity.PullRequestDTO{
-			PullRequestId:     exists.Id,
-			PullRequestName:   exists.PullRequestName,
-			AuthorId:          exists.AuthorId,
-			Status:            exists.Status,
-			AssignedReviewers: assignedIds,
-		},
-		ReplacedBy: &newAssignedIdPtr,
+	return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+}
+
+// checkMergeable returns an error wrapping ErrBaseMergeBlocked unless pr
+// has the approvals required by its review team and none of its current
+// reviewers requests changes. Teams requiring resolved comments also block
+// PRs with unresolved threads.
+func (s *PullRequestService) checkMergeable(
+	ctx context.Context,
+	db repository.Querier,
+	pr *entity.PullRequest,
+) error {
+	author, err := s.userRepo.GetById(ctx, db, pr.AuthorId)
+	if err != nil {
+		return err
+	}
+	team, err := s.reviewTeam(ctx, db, author, pr.RepositoryName)
+	if err != nil {
+		return err
+	}
+	reviews, err := s.reviewRepo.GetReviewsByPrId(ctx, db, pr.Id)
+	if err != nil {
+		return err
+	}
+
+	approvals := 0
+	for _, review := range reviews {
+		if review.Decision == entity.ReviewChangesRequested {
+			return errs.ErrChangesRequested
+		}
+		approvals++
+	}
+	if approvals < team.RequiredApprovals {
+		return errs.ErrNotEnoughApprovals
+	}
+
+	if team.RequireResolvedComments {
+		unresolved, err := s.commentRepo.CountUnresolvedThreads(ctx, db, pr.Id)
+		if err != nil {
+			return err
+		}
+		if unresolved > 0 {
+			return errs.ErrUnresolvedComments
+		}
+	}
+	return nil
+}
+
+// SubmitReview records the decision of an assigned reviewer on an open PR.
+// A reviewer has one decision per PR, a new review replaces the previous.
+func (s *PullRequestService) SubmitReview(
+	ctx context.Context,
+	dto entity.SubmitReviewDTO,
+) (*entity.PullRequestResponseDTO, error) {
+	if dto.Decision != entity.ReviewApproved && dto.Decision != entity.ReviewChangesRequested {
+		return nil, errs.ErrInvalidReview
+	}
+	if len(dto.Comment) > 4096 {
+		return nil, errs.ErrInvalidReview
+	}
+
+	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, dto.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	switch exists.Status {
+	case entity.StatusMerged:
+		return nil, errs.ErrReviewOnMergedPR
+	case entity.StatusClosed:
+		return nil, errs.ErrReviewOnClosedPR
+	case entity.StatusDraft:
+		return nil, errs.ErrDraftPR
+	}
+
+	assigned, err := s.assignedReviewerIds(ctx, s.pool, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(assigned, dto.UserId) {
+		return nil, errs.ErrUserNotAssigned
+	}
+
+	err = s.reviewRepo.SetReview(ctx, s.pool, &entity.Review{
+		PullRequestId: exists.Id,
+		UserId:        dto.UserId,
+		Decision:      dto.Decision,
+		Comment:       dto.Comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prDTO, err := s.pullRequestDTO(ctx, s.pool, exists)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+}
+
+func (s *PullRequestService) ClosePullRequest(
+	ctx context.Context,
+	dto entity.ClosePullRequestDTO,
+) (*entity.PullRequestResponseDTO, error) {
+	return s.changeStatus(ctx, dto.PullRequestId, dto.ActorId, entity.StatusClosed)
+}
+
+func (s *PullRequestService) ReopenPullRequest(
+	ctx context.Context,
+	dto entity.ReopenPullRequestDTO,
+) (*entity.PullRequestResponseDTO, error) {
+	return s.changeStatus(ctx, dto.PullRequestId, dto.ActorId, entity.StatusOpen)
+}
+
+// changeStatus closes a PR that isn't merged or reopens a closed one.
+// Reviewers are kept, so a reopened PR is reviewed by the same people, and
+// a draft that was closed is reopened as a draft. Repeated calls return the
+// PR unchanged.
+func (s *PullRequestService) changeStatus(
+	ctx context.Context,
+	prId string,
+	actorId string,
+	status string,
+) (*entity.PullRequestResponseDTO, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	exists, err := s.prRepo.GetPullRequestByIdForUpdate(ctx, tx, prId)
+	if err != nil {
+		return nil, err
+	}
+	if exists.Status == entity.StatusMerged {
+		return nil, errs.ErrCloseMergedPR
+	}
+
+	if (exists.Status == entity.StatusClosed) != (status == entity.StatusClosed) {
+		actor, err := s.actor(ctx, tx, actorId)
+		if err != nil {
+			return nil, err
+		}
+
+		if status != entity.StatusClosed {
+			status, err = s.statusBeforeClose(ctx, tx, exists.Id)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = s.prRepo.UpdatePullRequestStatus(ctx, tx, exists.Id, status)
+		if err != nil {
+			return nil, err
+		}
+		err = s.recordStatusChange(ctx, tx, exists.Id, actor, exists.Status, status)
+		if err != nil {
+			return nil, err
+		}
+
+		exists, err = s.prRepo.GetPullRequestById(ctx, tx, prId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prDTO, err := s.pullRequestDTO(ctx, tx, exists)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+	return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+}
+
+func (s *PullRequestService) ReassignPullRequest(
+	ctx context.Context,
+	dto entity.ReassignPullRequestDTO,
+) (*entity.PullRequestResponseDTO, error) {
+	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, dto.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	if exists.Status == entity.StatusMerged {
+		return nil, errs.ErrReassignOnMergedPR
+	}
+	if exists.Status == entity.StatusClosed {
+		return nil, errs.ErrReassignOnClosedPR
+	}
+	if exists.Status == entity.StatusDraft {
+		return nil, errs.ErrDraftPR
+	}
+	actorId, err := s.actor(ctx, s.pool, dto.ActorId)
+	if err != nil {
+		return nil, err
+	}
+	if dto.NewReviewerId != nil {
+		return s.reassignTo(ctx, exists, actorId, dto.OldReviewerId, *dto.NewReviewerId)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	plan, err := s.planReplacement(ctx, tx, exists, dto.OldReviewerId)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.selected) == 0 {
+		return nil, plan.req.shortage(errs.ErrNoActiveUsers)
+	}
+	if len(plan.current)+len(plan.selected) < plan.team.MinReviewers {
+		return nil, plan.req.shortage(errs.ErrNotEnoughReviewers)
+	}
+
+	assignmentId, err := s.applyReplacement(ctx, tx, exists, actorId, dto.OldReviewerId, plan)
+	if err != nil {
+		return nil, err
+	}
+	newAssignedIdPtr := plan.selected[0].UserId
+
+	assigned, err := s.userRepo.GetReviewersByPrId(ctx, tx, dto.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	assignedIds := make([]string, len(assigned))
+	for i, u := range assigned {
+		assignedIds[i] = u.Id
+	}
+	unresolved, err := s.commentRepo.CountUnresolvedThreads(ctx, tx, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := s.dependencies(ctx, tx, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+
+	createdAt := exists.CreatedAt.Format(time.RFC3339)
+	return &entity.PullRequestResponseDTO{
+		PullRequest: entity.PullRequestDTO{
+			PullRequestId:     exists.Id,
+			PullRequestName:   exists.PullRequestName,
+			Description:       exists.Description,
+			AuthorId:          exists.AuthorId,
+			Status:            exists.Status,
+			AssignedReviewers: assignedIds,
+			Labels:            plan.labels,
+			CreatedAt:         &createdAt,
+			UnresolvedThreads: unresolved,
+			ParentId:          exists.ParentId,
+			Dependencies:      dependencies,
+			RepositoryName:    exists.RepositoryName,
+			Number:            exists.Number,
+		},
+		ReplacedBy:        &newAssignedIdPtr,
+		FallbackReviewers: fallbackReviewers(plan.selected, plan.team.TeamName),
+		AssignmentId:      &assignmentId,
+		Assignment:        s.explainAssignment(plan.team, plan.req, plan.selected),
+	}, nil
+}
+
+// reassignTo replaces oldReviewerId on pr with the explicitly chosen
+// newReviewerId, bypassing the team strategy. The choice is stored as an
+// assignment with a single step offering only newReviewerId.
+func (s *PullRequestService) reassignTo(
+	ctx context.Context,
+	pr *entity.PullRequest,
+	actorId *string,
+	oldReviewerId string,
+	newReviewerId string,
+) (*entity.PullRequestResponseDTO, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	assigned, err := s.assignedReviewerIds(ctx, tx, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(assigned, oldReviewerId) {
+		return nil, errs.ErrUserNotAssigned
+	}
+	req, err := s.newAssignmentRequest(ctx, tx, s.nextSeed(), pr.AuthorId, nil, assigned...)
+	if err != nil {
+		return nil, err
+	}
+	user, stats, err := s.validateReviewer(ctx, tx, pr, req, assigned, newReviewerId)
+	if err != nil {
+		return nil, err
+	}
+	author, err := s.userRepo.GetById(ctx, tx, pr.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.reviewTeam(ctx, tx, author, pr.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+	s.manualStep(team, req, *stats)
+
+	err = s.prRepo.RemoveReviewerFromPullRequest(ctx, tx, pr.Id, oldReviewerId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.prRepo.AddReviewerToPullRequest(ctx, tx, pr.Id, newReviewerId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.recordReviewerChanges(
+		ctx,
+		tx,
+		pr.Id,
+		actorId,
+		entity.EventReviewerRemoved,
+		oldReviewerId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = s.recordReviewerChanges(ctx, tx, pr.Id, actorId, entity.EventReviewerAdded, newReviewerId)
+	if err != nil {
+		return nil, err
+	}
+	assignmentId, err := s.saveAssignment(
+		ctx,
+		tx,
+		pr.Id,
+		entity.AssignmentReassign,
+		req,
+		[]string{newReviewerId},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	prDTO, err := s.pullRequestDTO(ctx, tx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+	selected := []selectedReviewer{{UserId: user.Id, TeamName: user.TeamName}}
+	return &entity.PullRequestResponseDTO{
+		PullRequest:  *prDTO,
+		ReplacedBy:   &newReviewerId,
+		AssignmentId: &assignmentId,
+		Assignment:   s.explainAssignment(team, req, selected),
+	}, nil
+}
+
+func (s *PullRequestService) AddReviewer(
+	ctx context.Context,
+	dto entity.PullRequestReviewerDTO,
+) (*entity.PullRequestResponseDTO, error) {
+	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, dto.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	if exists.Status == entity.StatusMerged {
+		return nil, errs.ErrReassignOnMergedPR
+	}
+	if exists.Status == entity.StatusClosed {
+		return nil, errs.ErrReassignOnClosedPR
+	}
+	if exists.Status == entity.StatusDraft {
+		return nil, errs.ErrDraftPR
+	}
+	actorId, err := s.actor(ctx, s.pool, dto.ActorId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	assigned, err := s.assignedReviewerIds(ctx, tx, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.newAssignmentRequest(ctx, tx, s.nextSeed(), exists.AuthorId, nil, assigned...)
+	if err != nil {
+		return nil, err
+	}
+	_, _, err = s.validateReviewer(ctx, tx, exists, req, assigned, dto.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.prRepo.AddReviewerToPullRequest(ctx, tx, exists.Id, dto.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.recordReviewerChanges(
+		ctx,
+		tx,
+		exists.Id,
+		actorId,
+		entity.EventReviewerAdded,
+		dto.UserId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	prDTO, err := s.pullRequestDTO(ctx, tx, exists)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+	return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+}
+
+// RemoveReviewer unassigns a reviewer without picking a replacement. It
+// fails if the PR would be left with fewer reviewers than its review team
+// requires.
+func (s *PullRequestService) RemoveReviewer(
+	ctx context.Context,
+	dto entity.PullRequestReviewerDTO,
+) (*entity.PullRequestResponseDTO, error) {
+	exists, err := s.prRepo.GetPullRequestById(ctx, s.pool, dto.PullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	if exists.Status == entity.StatusMerged {
+		return nil, errs.ErrReassignOnMergedPR
+	}
+	if exists.Status == entity.StatusClosed {
+		return nil, errs.ErrReassignOnClosedPR
+	}
+	if exists.Status == entity.StatusDraft {
+		return nil, errs.ErrDraftPR
+	}
+	actorId, err := s.actor(ctx, s.pool, dto.ActorId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	assigned, err := s.assignedReviewerIds(ctx, tx, exists.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(assigned, dto.UserId) {
+		return nil, errs.ErrUserNotAssigned
+	}
+
+	author, err := s.userRepo.GetById(ctx, tx, exists.AuthorId)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.reviewTeam(ctx, tx, author, exists.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+	if len(assigned)-1 < team.MinReviewers {
+		return nil, errs.ErrNotEnoughReviewers
+	}
+
+	err = s.prRepo.RemoveReviewerFromPullRequest(ctx, tx, exists.Id, dto.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.recordReviewerChanges(
+		ctx,
+		tx,
+		exists.Id,
+		actorId,
+		entity.EventReviewerRemoved,
+		dto.UserId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	prDTO, err := s.pullRequestDTO(ctx, tx, exists)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+	return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+}
+
+// validateReviewer checks that userId can be assigned to pr by hand under
+// the same constraints as automatic selection: the user has to be present,
+// below the open reviews limit and not excluded by the rules of req. It
+// returns the user with its stats.
+func (s *PullRequestService) validateReviewer(
+	ctx context.Context,
+	db repository.Querier,
+	pr *entity.PullRequest,
+	req *assignmentRequest,
+	assigned []string,
+	userId string,
+) (*entity.User, *entity.UserStats, error) {
+	user, err := s.userRepo.GetById(ctx, db, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Id == pr.AuthorId {
+		return nil, nil, errs.ErrReviewerIsAuthor
+	}
+	if !user.IsActive {
+		return nil, nil, errs.ErrReviewerInactive
+	}
+	if slices.Contains(assigned, user.Id) {
+		return nil, nil, errs.ErrReviewerAlreadyAssigned
+	}
+	if slices.Contains(req.ruleExcluded, user.Id) {
+		return nil, nil, errs.ErrReviewerExcluded
+	}
+
+	stats, err := s.prRepo.GetOpenPullRequestsByUserIds(ctx, db, []string{user.Id})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stats) == 0 {
+		return nil, nil, errs.ErrReviewerAbsent
+	}
+	if atCapacity(stats[0]) {
+		return nil, nil, errs.ErrReviewerAtCapacity
+	}
+	return user, &stats[0], nil
+}
+
+func (s *PullRequestService) assignedReviewerIds(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]string, error) {
+	reviewers, err := s.userRepo.GetReviewersByPrId(ctx, db, prId)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(reviewers))
+	for i, u := range reviewers {
+		ids[i] = u.Id
+	}
+	return ids, nil
+}
+
+// pullRequestDTO builds the response view of pr with its current
+// reviewers, labels, reviews, dependencies and the number of unresolved
+// comment threads.
+func (s *PullRequestService) pullRequestDTO(
+	ctx context.Context,
+	db repository.Querier,
+	pr *entity.PullRequest,
+) (*entity.PullRequestDTO, error) {
+	assigned, err := s.assignedReviewerIds(ctx, db, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.prRepo.GetPullRequestLabels(ctx, db, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviewRepo.GetReviewsByPrId(ctx, db, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	unresolved, err := s.commentRepo.CountUnresolvedThreads(ctx, db, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := s.dependencies(ctx, db, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestDTO(pr, assigned, labels, reviews, unresolved, dependencies), nil
+}
+
+func newPullRequestDTO(
+	pr *entity.PullRequest,
+	assigned []string,
+	labels []string,
+	reviews []entity.Review,
+	unresolved int,
+	dependencies []entity.PullRequestDependencyDTO,
+) *entity.PullRequestDTO {
+	createdAt := pr.CreatedAt.Format(time.RFC3339)
+	var mergedAt *string
+	if pr.MergedAt != nil {
+		fmtTime := pr.MergedAt.Format(time.RFC3339)
+		mergedAt = &fmtTime
+	}
+	var closedAt *string
+	if pr.ClosedAt != nil {
+		fmtTime := pr.ClosedAt.Format(time.RFC3339)
+		closedAt = &fmtTime
+	}
+	return &entity.PullRequestDTO{
+		PullRequestId:     pr.Id,
+		PullRequestName:   pr.PullRequestName,
+		Description:       pr.Description,
+		AuthorId:          pr.AuthorId,
+		Status:            pr.Status,
+		AssignedReviewers: assigned,
+		Labels:            labels,
+		Reviews:           reviewDTOs(reviews),
+		CreatedAt:         &createdAt,
+		MergedAt:          mergedAt,
+		ClosedAt:          closedAt,
+		UnresolvedThreads: unresolved,
+		ParentId:          pr.ParentId,
+		Dependencies:      dependencies,
+		RepositoryName:    pr.RepositoryName,
+		Number:            pr.Number,
+	}
+}
+
+func reviewDTOs(reviews []entity.Review) []entity.ReviewDTO {
+	res := make([]entity.ReviewDTO, len(reviews))
+	for i, r := range reviews {
+		res[i] = entity.ReviewDTO{
+			UserId:      r.UserId,
+			Decision:    r.Decision,
+			Comment:     r.Comment,
+			SubmittedAt: r.SubmittedAt.Format(time.RFC3339),
+		}
+	}
+	return res
+}
+
+// ReassignUserReviews replaces userId on every pull request that is not
+// merged yet, so closed and draft ones do not keep the user after reopening.
+// A pull request left below the minimum of its review team is reported as
+// uncovered.
+func (s *PullRequestService) ReassignUserReviews(
+	ctx context.Context,
+	db repository.Querier,
+	userId string,
+) ([]entity.AffectedPullRequestDTO, error) {
+	prs, err := s.prRepo.GetPullRequestsByReviewerId(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var affected []entity.AffectedPullRequestDTO
+	for _, pr := range prs {
+		if pr.Status == entity.StatusMerged {
+			continue
+		}
+
+		plan, err := s.planReplacement(ctx, db, &pr, userId)
+		if err != nil {
+			return nil, err
+		}
+		if len(plan.selected) == 0 {
+			s.logger.Debug(
+				"no replacement for deactivated reviewer",
+				"prId",
+				pr.Id,
+				"userId",
+				userId,
+			)
+			affected = append(affected, entity.AffectedPullRequestDTO{
+				PullRequestId: pr.Id,
+				Uncovered:     true,
+			})
+			continue
+		}
+
+		_, err = s.applyReplacement(ctx, db, &pr, nil, userId, plan)
+		if err != nil {
+			return nil, err
+		}
+		affected = append(affected, entity.AffectedPullRequestDTO{
+			PullRequestId: pr.Id,
+			ReplacedBy:    selectedIds(plan.selected),
+			Uncovered:     len(plan.current)+len(plan.selected) < plan.team.MinReviewers,
+		})
+	}
+	return affected, nil
+}
+
+func (s *PullRequestService) GetAssignments(
+	ctx context.Context,
+	prId string,
+) (*entity.PullRequestAssignmentsDTO, error) {
+	_, err := s.prRepo.GetPullRequestById(ctx, s.pool, prId)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.assignmentRepo.GetAssignmentsByPrId(ctx, s.pool, prId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.PullRequestAssignmentsDTO{
+		PullRequestId: prId,
+		Assignments:   make([]entity.AssignmentDTO, len(assignments)),
+	}
+	for i, a := range assignments {
+		result.Assignments[i] = entity.AssignmentDTO{
+			AssignmentId: a.Id,
+			Kind:         a.Kind,
+			Seed:         a.Seed,
+			Reviewers:    a.Reviewers,
+			Steps:        a.Steps,
+			CreatedAt:    a.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return result, nil
+}
+
+func (s *PullRequestService) ReplayAssignment(
+	ctx context.Context,
+	prId string,
+	assignmentId int64,
+) (*entity.AssignmentReplayDTO, error) {
+	assignment, err := s.assignmentRepo.GetAssignmentById(ctx, s.pool, assignmentId)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.PullRequestId != prId {
+		return nil, errs.ErrNotFound("assignment", "id", assignmentId)
+	}
+
+	replayed, err := replayAssignment(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AssignmentReplayDTO{
+		AssignmentId:      assignment.Id,
+		PullRequestId:     assignment.PullRequestId,
+		Seed:              assignment.Seed,
+		Reviewers:         assignment.Reviewers,
+		ReplayedReviewers: replayed,
+		Matches:           slices.Equal(assignment.Reviewers, replayed),
 	}, nil
 }
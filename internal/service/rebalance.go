@@ -0,0 +1,237 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+// RebalanceTeam moves open reviews from the most loaded available members
+// of a team to the least loaded ones until their loads differ by at most
+// one or no review can be moved. With dto.DryRun the moves are only
+// planned.
+func (s *PullRequestService) RebalanceTeam(
+	ctx context.Context,
+	dto entity.RebalanceTeamDTO,
+) (*entity.RebalanceTeamResultDTO, error) {
+	_, err := s.teamRepo.GetTeam(ctx, s.pool, dto.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	actorId, err := s.actor(ctx, s.pool, dto.ActorId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	moves, loads, err := s.planRebalance(ctx, tx, dto.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dto.DryRun {
+		for _, move := range moves {
+			err = s.prRepo.RemoveReviewerFromPullRequest(
+				ctx,
+				tx,
+				move.PullRequestId,
+				move.FromUserId,
+			)
+			if err != nil {
+				return nil, err
+			}
+			err = s.prRepo.AddReviewerToPullRequest(ctx, tx, move.PullRequestId, move.ToUserId)
+			if err != nil {
+				return nil, err
+			}
+			err = s.recordReviewerChanges(
+				ctx,
+				tx,
+				move.PullRequestId,
+				actorId,
+				entity.EventReviewerRemoved,
+				move.FromUserId,
+			)
+			if err != nil {
+				return nil, err
+			}
+			err = s.recordReviewerChanges(
+				ctx,
+				tx,
+				move.PullRequestId,
+				actorId,
+				entity.EventReviewerAdded,
+				move.ToUserId,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err = tx.Commit(ctx); err != nil {
+			return nil, errs.ErrInternal("error commit transaction", err)
+		}
+	}
+
+	return &entity.RebalanceTeamResultDTO{
+		TeamName: dto.TeamName,
+		DryRun:   dto.DryRun,
+		Moves:    moves,
+		Loads:    loads,
+	}, nil
+}
+
+// planRebalance returns the moves balancing open reviews of teamName and
+// the loads of its available members after them. A review is moved only to
+// a member that is not the author, not assigned to the PR yet, not
+// excluded by the rules of the author and below its open reviews limit.
+// The plan is deterministic: members and PRs are walked in id order.
+func (s *PullRequestService) planRebalance(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+) ([]entity.ReviewMoveDTO, []entity.UserStatsDTO, error) {
+	members, err := s.prRepo.GetOpenPullRequestsByTeamMembers(ctx, db, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+	slices.SortFunc(members, func(a, b entity.UserStats) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	loads := make(map[string]int, len(members))
+	reviews := make(map[string][]string, len(members))
+	prs := make(map[string]entity.PullRequest)
+	reviewers := make(map[string][]string)
+	for _, member := range members {
+		loads[member.Id] = member.OpenPullRequestsCount
+
+		userPrs, err := s.prRepo.GetPullRequestsByReviewerId(ctx, db, member.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pr := range userPrs {
+			if pr.Status != entity.StatusOpen {
+				continue
+			}
+			reviews[member.Id] = append(reviews[member.Id], pr.Id)
+			if _, ok := prs[pr.Id]; ok {
+				continue
+			}
+			prs[pr.Id] = pr
+			reviewers[pr.Id], err = s.assignedReviewerIds(ctx, db, pr.Id)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		slices.Sort(reviews[member.Id])
+	}
+
+	excluded := make(map[string][]string)
+	excludedBy := func(authorId string) ([]string, error) {
+		if ids, ok := excluded[authorId]; ok {
+			return ids, nil
+		}
+		rules, err := s.ruleRepo.GetRulesByAuthorId(ctx, db, authorId)
+		if err != nil {
+			return nil, err
+		}
+		ids := []string{}
+		for _, rule := range rules {
+			if rule.Kind == entity.RuleExclude {
+				ids = append(ids, rule.ReviewerId)
+			}
+		}
+		excluded[authorId] = ids
+		return ids, nil
+	}
+
+	// movable returns a PR reviewed by from that can be handed to to.
+	movable := func(from string, to entity.UserStats) (string, error) {
+		if to.MaxOpenReviews != nil && loads[to.Id] >= *to.MaxOpenReviews {
+			return "", nil
+		}
+		for _, prId := range reviews[from] {
+			pr := prs[prId]
+			if pr.AuthorId == to.Id || slices.Contains(reviewers[prId], to.Id) {
+				continue
+			}
+			ids, err := excludedBy(pr.AuthorId)
+			if err != nil {
+				return "", err
+			}
+			if !slices.Contains(ids, to.Id) {
+				return prId, nil
+			}
+		}
+		return "", nil
+	}
+
+	moves := []entity.ReviewMoveDTO{}
+	for {
+		byLoad := slices.Clone(members)
+		slices.SortStableFunc(byLoad, func(a, b entity.UserStats) int {
+			return cmp.Compare(loads[b.Id], loads[a.Id])
+		})
+
+		var move *entity.ReviewMoveDTO
+		for _, from := range byLoad {
+			for i := len(byLoad) - 1; i >= 0 && move == nil; i-- {
+				to := byLoad[i]
+				if loads[from.Id]-loads[to.Id] < 2 {
+					break
+				}
+				prId, err := movable(from.Id, to)
+				if err != nil {
+					return nil, nil, err
+				}
+				if prId != "" {
+					move = &entity.ReviewMoveDTO{
+						PullRequestId: prId,
+						FromUserId:    from.Id,
+						ToUserId:      to.Id,
+					}
+				}
+			}
+			if move != nil {
+				break
+			}
+		}
+		if move == nil {
+			break
+		}
+
+		moves = append(moves, *move)
+		loads[move.FromUserId]--
+		loads[move.ToUserId]++
+		from := reviews[move.FromUserId]
+		reviews[move.FromUserId] = slices.DeleteFunc(from, func(id string) bool {
+			return id == move.PullRequestId
+		})
+		reviews[move.ToUserId] = append(reviews[move.ToUserId], move.PullRequestId)
+		slices.Sort(reviews[move.ToUserId])
+		for i, id := range reviewers[move.PullRequestId] {
+			if id == move.FromUserId {
+				reviewers[move.PullRequestId][i] = move.ToUserId
+			}
+		}
+	}
+
+	result := make([]entity.UserStatsDTO, len(members))
+	for i, member := range members {
+		result[i] = entity.UserStatsDTO{
+			UserId:           member.Id,
+			Username:         member.Username,
+			OpenPullRequests: loads[member.Id],
+		}
+	}
+	return moves, result, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+func (s *PullRequestService) GetPullRequestByNumber(
+	ctx context.Context,
+	repositoryName string,
+	number int,
+) (*entity.PullRequestResponseDTO, error) {
+	exists, err := s.prRepo.GetPullRequestByNumber(ctx, s.pool, repositoryName, number)
+	if err != nil {
+		return nil, err
+	}
+	prDTO, err := s.pullRequestDTO(ctx, s.pool, exists)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PullRequestResponseDTO{PullRequest: *prDTO}, nil
+}
+
+// repositoryPullRequestId checks the repository and the number of a new PR
+// and returns its id, always "<repository>#<number>", so a PR of a
+// repository is found by either. dto must not set an id of its own. The
+// number has to be free in the repository.
+func (s *PullRequestService) repositoryPullRequestId(
+	ctx context.Context,
+	dto entity.PullRequestCreateDTO,
+) (string, error) {
+	if dto.RepositoryName == nil || dto.Number == nil || *dto.Number < 1 {
+		return "", errs.ErrInvalidPullRequestNumber
+	}
+	if dto.PullRequestId != "" {
+		return "", errs.ErrRepositoryPullRequestId
+	}
+	_, err := s.teamRepo.GetRepository(ctx, s.pool, *dto.RepositoryName)
+	if err != nil {
+		return "", err
+	}
+	exists, err := s.prRepo.GetPullRequestByNumber(ctx, s.pool, *dto.RepositoryName, *dto.Number)
+	if err != nil && !errors.Is(err, errs.ErrBaseNotFound) {
+		return "", err
+	}
+	if exists != nil {
+		return "", errs.ErrPullRequestAlreadyExists
+	}
+	return fmt.Sprintf("%s#%d", *dto.RepositoryName, *dto.Number), nil
+}
+
+// reviewTeam returns the team reviewing PRs of author in repositoryName:
+// the owning team if the repository routes reviews to it, the author's
+// team otherwise. The team settings decide the reviewer counts, the
+// strategy, code owners and approvals of such PRs.
+func (s *PullRequestService) reviewTeam(
+	ctx context.Context,
+	db repository.Querier,
+	author *entity.User,
+	repositoryName *string,
+) (*entity.Team, error) {
+	teamName := author.TeamName
+	if repositoryName != nil {
+		repo, err := s.teamRepo.GetRepository(ctx, db, *repositoryName)
+		if err != nil {
+			return nil, err
+		}
+		if repo.RouteReviewsToOwner {
+			teamName = repo.TeamName
+		}
+	}
+	return s.teamRepo.GetTeam(ctx, db, teamName)
+}
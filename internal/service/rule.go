@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReviewerRuleService struct {
+	logger   *slog.Logger
+	pool     *pgxpool.Pool
+	ruleRepo repository.BaseReviewerRuleRepository
+	userRepo repository.BaseUserRepository
+}
+
+func NewReviewerRuleService(
+	baseLogger *slog.Logger,
+	pool *pgxpool.Pool,
+	ruleRepo repository.BaseReviewerRuleRepository,
+	userRepo repository.BaseUserRepository,
+) BaseReviewerRuleService {
+	logger := baseLogger.With("module", "ruleservice")
+	return &ReviewerRuleService{
+		logger:   logger,
+		pool:     pool,
+		ruleRepo: ruleRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *ReviewerRuleService) GetRules(
+	ctx context.Context,
+	authorId string,
+) (*entity.AuthorReviewerRulesDTO, error) {
+	_, err := s.userRepo.GetById(ctx, s.pool, authorId)
+	if err != nil {
+		s.logger.Debug("failed to GetRules: GetById failed", "authorId", authorId, "err", err)
+		return nil, err
+	}
+
+	rules, err := s.ruleRepo.GetRulesByAuthorId(ctx, s.pool, authorId)
+	if err != nil {
+		s.logger.Debug("failed to GetRules: GetRulesByAuthorId failed", "err", err)
+		return nil, err
+	}
+
+	rulesDTO := make([]entity.ReviewerRuleDTO, len(rules))
+	for i, rule := range rules {
+		rulesDTO[i] = entity.ReviewerRuleDTO{
+			AuthorId:   rule.AuthorId,
+			ReviewerId: rule.ReviewerId,
+			Kind:       rule.Kind,
+		}
+	}
+	return &entity.AuthorReviewerRulesDTO{
+		AuthorId: authorId,
+		Rules:    rulesDTO,
+	}, nil
+}
+
+func (s *ReviewerRuleService) SetRule(
+	ctx context.Context,
+	dto entity.ReviewerRuleDTO,
+) (*entity.AuthorReviewerRulesDTO, error) {
+	if dto.Kind != entity.RuleExclude && dto.Kind != entity.RulePrefer {
+		s.logger.Debug("failed to SetRule: invalid kind", "dto", dto)
+		return nil, errs.ErrInvalidReviewerRule
+	}
+	if dto.AuthorId == dto.ReviewerId {
+		s.logger.Debug("failed to SetRule: author and reviewer are the same", "dto", dto)
+		return nil, errs.ErrInvalidReviewerRule
+	}
+
+	for _, id := range []string{dto.AuthorId, dto.ReviewerId} {
+		_, err := s.userRepo.GetById(ctx, s.pool, id)
+		if err != nil {
+			s.logger.Debug("failed to SetRule: GetById failed", "id", id, "err", err)
+			return nil, err
+		}
+	}
+
+	err := s.ruleRepo.SetRule(ctx, s.pool, &entity.ReviewerRule{
+		AuthorId:   dto.AuthorId,
+		ReviewerId: dto.ReviewerId,
+		Kind:       dto.Kind,
+	})
+	if err != nil {
+		s.logger.Debug("failed to SetRule: SetRule failed", "err", err)
+		return nil, err
+	}
+	return s.GetRules(ctx, dto.AuthorId)
+}
+
+func (s *ReviewerRuleService) RemoveRule(
+	ctx context.Context,
+	dto entity.RemoveReviewerRuleDTO,
+) (*entity.AuthorReviewerRulesDTO, error) {
+	err := s.ruleRepo.RemoveRule(ctx, s.pool, dto.AuthorId, dto.ReviewerId)
+	if err != nil {
+		s.logger.Debug("failed to RemoveRule: RemoveRule failed", "dto", dto, "err", err)
+		return nil, err
+	}
+	return s.GetRules(ctx, dto.AuthorId)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+)
+
+// ReviewerCandidate is a user that can be assigned as a reviewer
+// together with the data strategies use to rank it.
+type ReviewerCandidate struct {
+	UserId        string
+	OpenReviews   int
+	RecentReviews int
+}
+
+// ReviewerSelector picks up to count reviewers out of candidates.
+// Candidates are already filtered: author, inactive users and
+// current reviewers are never passed in. Selectors are stateless: the
+// result depends only on the arguments, so a selection can be replayed
+// with a generator built from the same seed. cursor is the reviewer picked
+// last in the team and is used only by round robin.
+type ReviewerSelector interface {
+	Select(rng *rand.Rand, cursor string, candidates []ReviewerCandidate, count int) []string
+}
+
+func NewReviewerSelector(strategy string) (ReviewerSelector, error) {
+	switch strategy {
+	case entity.StrategyRandom:
+		return &randomSelector{}, nil
+	case entity.StrategyRoundRobin:
+		return &roundRobinSelector{}, nil
+	case "", entity.StrategyLeastLoaded:
+		return &leastLoadedSelector{}, nil
+	case entity.StrategyWeighted:
+		return &weightedSelector{weight: openReviewsWeight}, nil
+	case entity.StrategyRecentHistory:
+		return &weightedSelector{weight: recentReviewsWeight}, nil
+	}
+	return nil, errs.ErrUnknownReviewerStrategy
+}
+
+func ValidateReviewerStrategy(strategy string) error {
+	_, err := NewReviewerSelector(strategy)
+	return err
+}
+
+// NewAssignmentRand returns the generator used for a single assignment.
+func NewAssignmentRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+func candidateIds(candidates []ReviewerCandidate) []string {
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.UserId
+	}
+	return ids
+}
+
+// randomSelector picks reviewers uniformly at random.
+type randomSelector struct{}
+
+func (r *randomSelector) Select(
+	rng *rand.Rand,
+	_ string,
+	candidates []ReviewerCandidate,
+	count int,
+) []string {
+	shuffled := slices.Clone(candidates)
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return candidateIds(shuffled[:min(count, len(shuffled))])
+}
+
+// roundRobinSelector walks team members ordered by id, continuing
+// after the cursor.
+type roundRobinSelector struct{}
+
+func (r *roundRobinSelector) Select(
+	_ *rand.Rand,
+	cursor string,
+	candidates []ReviewerCandidate,
+	count int,
+) []string {
+	if len(candidates) == 0 || count <= 0 {
+		return []string{}
+	}
+
+	sorted := slices.SortedFunc(slices.Values(candidates), func(a, b ReviewerCandidate) int {
+		return cmp.Compare(a.UserId, b.UserId)
+	})
+
+	start := 0
+	if cursor != "" {
+		start = len(sorted)
+		for i, c := range sorted {
+			if c.UserId > cursor {
+				start = i
+				break
+			}
+		}
+	}
+
+	n := min(count, len(sorted))
+	picked := make([]string, n)
+	for i := range n {
+		picked[i] = sorted[(start+i)%len(sorted)].UserId
+	}
+	return picked
+}
+
+// leastLoadedSelector prefers candidates with the fewest open reviews,
+// breaking ties randomly.
+type leastLoadedSelector struct{}
+
+func (l *leastLoadedSelector) Select(
+	rng *rand.Rand,
+	_ string,
+	candidates []ReviewerCandidate,
+	count int,
+) []string {
+	shuffled := slices.Clone(candidates)
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	slices.SortStableFunc(shuffled, func(a, b ReviewerCandidate) int {
+		return cmp.Compare(a.OpenReviews, b.OpenReviews)
+	})
+	return candidateIds(shuffled[:min(count, len(shuffled))])
+}
+
+// weightedSelector picks at random with probability proportional to
+// weight of the candidate.
+type weightedSelector struct {
+	weight func(c ReviewerCandidate) float64
+}
+
+// openReviewsWeight is inversely proportional to the number of open reviews.
+func openReviewsWeight(c ReviewerCandidate) float64 {
+	return 1 / float64(1+c.OpenReviews)
+}
+
+// recentReviewsWeight is inversely proportional to the number of recently
+// completed reviews.
+func recentReviewsWeight(c ReviewerCandidate) float64 {
+	return 1 / float64(1+c.RecentReviews)
+}
+
+func (w *weightedSelector) Select(
+	rng *rand.Rand,
+	_ string,
+	candidates []ReviewerCandidate,
+	count int,
+) []string {
+	pool := slices.Clone(candidates)
+	picked := make([]string, 0, min(count, len(pool)))
+
+	for len(picked) < count && len(pool) > 0 {
+		total := 0.0
+		for _, c := range pool {
+			total += w.weight(c)
+		}
+
+		idx := len(pool) - 1
+		point := rng.Float64() * total
+		for i, c := range pool {
+			point -= w.weight(c)
+			if point < 0 {
+				idx = i
+				break
+			}
+		}
+
+		picked = append(picked, pool[idx].UserId)
+		pool = slices.Delete(pool, idx, idx+1)
+	}
+	return picked
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+)
+
+// checkParent returns ErrDependencyCycle if stacking prId on parentId would
+// make prId depend on itself.
+func (s *PullRequestService) checkParent(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	parentId string,
+) error {
+	if parentId == prId {
+		return errs.ErrDependencyCycle
+	}
+	_, err := s.prRepo.GetPullRequestById(ctx, db, parentId)
+	if err != nil {
+		return err
+	}
+	ancestors, err := s.prRepo.GetPullRequestAncestors(ctx, db, parentId)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(ancestors, func(pr entity.PullRequest) bool {
+		return pr.Id == prId
+	}) {
+		return errs.ErrDependencyCycle
+	}
+	return nil
+}
+
+// checkAncestorsMerged returns ErrParentNotMerged while a PR that pr is
+// stacked on is still open or a draft. Closed ancestors don't block, they
+// won't be merged.
+func (s *PullRequestService) checkAncestorsMerged(
+	ctx context.Context,
+	db repository.Querier,
+	pr *entity.PullRequest,
+) error {
+	ancestors, err := s.prRepo.GetPullRequestAncestors(ctx, db, pr.Id)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.Status == entity.StatusOpen || ancestor.Status == entity.StatusDraft {
+			return errs.ErrParentNotMerged
+		}
+	}
+	return nil
+}
+
+func (s *PullRequestService) dependencies(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+) ([]entity.PullRequestDependencyDTO, error) {
+	ancestors, err := s.prRepo.GetPullRequestAncestors(ctx, db, prId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]entity.PullRequestDependencyDTO, len(ancestors))
+	for i, pr := range ancestors {
+		res[i] = entity.PullRequestDependencyDTO{
+			PullRequestId: pr.Id,
+			Status:        pr.Status,
+		}
+	}
+	return res, nil
+}
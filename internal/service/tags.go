@@ -0,0 +1,23 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+)
+
+// normalizeTags lowercases and deduplicates user skills or PR labels.
+func normalizeTags(tags []string) ([]string, error) {
+	var result []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || len(tag) > 64 {
+			return nil, errs.ErrInvalidTag
+		}
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
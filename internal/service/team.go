@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
 	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
@@ -38,6 +40,62 @@ func (s *TeamService) AddTeam(
 	ctx context.Context,
 	dto entity.TeamDTO,
 ) (*entity.ResponseTeamDTO, error) {
+	if dto.ReviewerStrategy == "" {
+		dto.ReviewerStrategy = entity.DefaultReviewerStrategy
+	}
+	if err := ValidateReviewerStrategy(dto.ReviewerStrategy); err != nil {
+		s.logger.Debug("failed to AddTeam: invalid reviewer strategy", "dto", dto, "err", err)
+		return nil, err
+	}
+	minReviewers := entity.DefaultMinReviewers
+	if dto.MinReviewers != nil {
+		minReviewers = *dto.MinReviewers
+	}
+	maxReviewers := entity.DefaultMaxReviewers
+	if dto.MaxReviewers != nil {
+		maxReviewers = *dto.MaxReviewers
+	}
+	if minReviewers < 0 || maxReviewers < 1 || minReviewers > maxReviewers {
+		s.logger.Debug("failed to AddTeam: invalid reviewer count", "dto", dto)
+		return nil, errs.ErrInvalidReviewerCount
+	}
+	if dto.MaxOpenReviews != nil && *dto.MaxOpenReviews < 0 {
+		s.logger.Debug("failed to AddTeam: invalid open reviews limit", "dto", dto)
+		return nil, errs.ErrInvalidMaxOpenReviews
+	}
+	reviewWindowDays := entity.DefaultReviewWindowDays
+	if dto.ReviewWindowDays != nil {
+		reviewWindowDays = *dto.ReviewWindowDays
+	}
+	if reviewWindowDays < 1 {
+		s.logger.Debug("failed to AddTeam: invalid review window", "dto", dto)
+		return nil, errs.ErrInvalidReviewWindow
+	}
+	requiredApprovals := 0
+	if dto.RequiredApprovals != nil {
+		requiredApprovals = *dto.RequiredApprovals
+	}
+	if requiredApprovals < 0 || requiredApprovals > maxReviewers {
+		s.logger.Debug("failed to AddTeam: invalid required approvals", "dto", dto)
+		return nil, errs.ErrInvalidRequiredApprovals
+	}
+	requireResolvedComments := false
+	if dto.RequireResolvedComments != nil {
+		requireResolvedComments = *dto.RequireResolvedComments
+	}
+	for i, member := range dto.Members {
+		skills, err := normalizeTags(member.Skills)
+		if err != nil {
+			s.logger.Debug("failed to AddTeam: invalid member skills", "dto", dto, "err", err)
+			return nil, err
+		}
+		dto.Members[i].Skills = skills
+		if member.MaxOpenReviews != nil && *member.MaxOpenReviews < 0 {
+			s.logger.Debug("failed to AddTeam: invalid member open reviews limit", "dto", dto)
+			return nil, errs.ErrInvalidMaxOpenReviews
+		}
+	}
+
 	exists, err := s.teamRepo.GetTeam(ctx, s.pool, dto.TeamName)
 	if err != nil && !errors.Is(err, errs.ErrBaseNotFound) {
 		s.logger.Debug("failed to AddTeam: error in GetTeam", "dto", dto, "err", err)
@@ -58,7 +116,16 @@ func (s *TeamService) AddTeam(
 	}
 	defer tx.Rollback(ctx)
 
-	err = s.teamRepo.AddTeam(ctx, tx, &entity.Team{TeamName: dto.TeamName})
+	err = s.teamRepo.AddTeam(ctx, tx, &entity.Team{
+		TeamName:                dto.TeamName,
+		ReviewerStrategy:        dto.ReviewerStrategy,
+		MinReviewers:            minReviewers,
+		MaxReviewers:            maxReviewers,
+		MaxOpenReviews:          dto.MaxOpenReviews,
+		ReviewWindowDays:        reviewWindowDays,
+		RequiredApprovals:       requiredApprovals,
+		RequireResolvedComments: requireResolvedComments,
+	})
 	if err != nil {
 		s.logger.Debug("failed to AddTeam: error in AddTeam", "dto", dto, "err", err)
 		return nil, err
@@ -78,6 +145,36 @@ func (s *TeamService) AddTeam(
 		s.logger.Debug("failed to AddTeam: error in AddUsers", "dto", dto, "err", err)
 		return nil, err
 	}
+	for _, member := range dto.Members {
+		if len(member.Skills) > 0 {
+			err = s.userRepo.SetSkills(ctx, tx, member.UserId, member.Skills)
+			if err != nil {
+				s.logger.Debug("failed to AddTeam: error in SetSkills", "dto", dto, "err", err)
+				return nil, err
+			}
+		}
+		if member.MaxOpenReviews != nil {
+			err = s.userRepo.SetMaxOpenReviews(ctx, tx, member.UserId, member.MaxOpenReviews)
+			if err != nil {
+				s.logger.Debug(
+					"failed to AddTeam: error in SetMaxOpenReviews",
+					"dto",
+					dto,
+					"err",
+					err,
+				)
+				return nil, err
+			}
+		}
+	}
+
+	if len(dto.FallbackTeams) > 0 {
+		err = s.setFallbackTeams(ctx, tx, dto.TeamName, dto.FallbackTeams)
+		if err != nil {
+			s.logger.Debug("failed to AddTeam: error in setFallbackTeams", "dto", dto, "err", err)
+			return nil, err
+		}
+	}
 
 	if err = tx.Commit(ctx); err != nil {
 		s.logger.Debug("failed to AddTeam: failed commiting result", "dto", dto, "err", err)
@@ -85,8 +182,16 @@ func (s *TeamService) AddTeam(
 	}
 	return &entity.ResponseTeamDTO{
 		Team: entity.TeamDTO{
-			TeamName: dto.TeamName,
-			Members:  dto.Members,
+			TeamName:                dto.TeamName,
+			ReviewerStrategy:        dto.ReviewerStrategy,
+			MinReviewers:            &minReviewers,
+			MaxReviewers:            &maxReviewers,
+			MaxOpenReviews:          dto.MaxOpenReviews,
+			ReviewWindowDays:        &reviewWindowDays,
+			RequiredApprovals:       &requiredApprovals,
+			RequireResolvedComments: &requireResolvedComments,
+			FallbackTeams:           dto.FallbackTeams,
+			Members:                 dto.Members,
 		},
 	}, nil
 }
@@ -109,17 +214,388 @@ func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*entity.Tea
 		)
 		return nil, err
 	}
+	fallbacks, err := s.teamRepo.GetFallbackTeams(ctx, s.pool, teamName)
+	if err != nil {
+		s.logger.Debug(
+			"failed to GetTeam: error in GetFallbackTeams",
+			"teamName",
+			teamName,
+			"err",
+			err,
+		)
+		return nil, err
+	}
+	fallbackNames := make([]string, len(fallbacks))
+	for i, team := range fallbacks {
+		fallbackNames[i] = team.TeamName
+	}
+
+	userIds := make([]string, len(users))
+	for i, user := range users {
+		userIds[i] = user.Id
+	}
+	skills, err := s.userRepo.GetSkillsByUserIds(ctx, s.pool, userIds)
+	if err != nil {
+		s.logger.Debug(
+			"failed to GetTeam: error in GetSkillsByUserIds",
+			"teamName",
+			teamName,
+			"err",
+			err,
+		)
+		return nil, err
+	}
+
 	usersDTO := make([]entity.UserDTO, len(users))
 	for i, user := range users {
 		usersDTO[i] = entity.UserDTO{
-			UserId:   user.Id,
-			Username: user.Username,
-			IsActive: user.IsActive,
+			UserId:         user.Id,
+			Username:       user.Username,
+			IsActive:       user.IsActive,
+			MaxOpenReviews: user.MaxOpenReviews,
+		}
+		for _, skill := range skills {
+			if skill.UserId == user.Id {
+				usersDTO[i].Skills = append(usersDTO[i].Skills, skill.Skill)
+			}
 		}
 	}
 
 	return &entity.TeamDTO{
-		TeamName: exists.TeamName,
-		Members:  usersDTO,
+		TeamName:                exists.TeamName,
+		ReviewerStrategy:        exists.ReviewerStrategy,
+		MinReviewers:            &exists.MinReviewers,
+		MaxReviewers:            &exists.MaxReviewers,
+		MaxOpenReviews:          exists.MaxOpenReviews,
+		ReviewWindowDays:        &exists.ReviewWindowDays,
+		RequiredApprovals:       &exists.RequiredApprovals,
+		RequireResolvedComments: &exists.RequireResolvedComments,
+		FallbackTeams:           fallbackNames,
+		Members:                 usersDTO,
+	}, nil
+}
+
+// SetTeamSettings changes the settings of an existing team that dto sets,
+// validated like in AddTeam.
+func (s *TeamService) SetTeamSettings(
+	ctx context.Context,
+	dto entity.TeamSettingsDTO,
+) (*entity.TeamDTO, error) {
+	update := entity.TeamUpdate{
+		ReviewerStrategy:        dto.ReviewerStrategy,
+		MinReviewers:            dto.MinReviewers,
+		MaxReviewers:            dto.MaxReviewers,
+		MaxOpenReviews:          dto.MaxOpenReviews,
+		ClearMaxOpenReviews:     dto.ClearMaxOpenReviews,
+		ReviewWindowDays:        dto.ReviewWindowDays,
+		RequiredApprovals:       dto.RequiredApprovals,
+		RequireResolvedComments: dto.RequireResolvedComments,
+	}
+	if update == (entity.TeamUpdate{}) {
+		s.logger.Debug("failed to SetTeamSettings: nothing to update", "dto", dto)
+		return nil, errs.ErrEmptyTeamUpdate
+	}
+	if dto.ReviewerStrategy != nil {
+		if err := ValidateReviewerStrategy(*dto.ReviewerStrategy); err != nil {
+			s.logger.Debug(
+				"failed to SetTeamSettings: invalid reviewer strategy",
+				"dto",
+				dto,
+				"err",
+				err,
+			)
+			return nil, err
+		}
+	}
+	if dto.MaxOpenReviews != nil && *dto.MaxOpenReviews < 0 {
+		s.logger.Debug("failed to SetTeamSettings: invalid open reviews limit", "dto", dto)
+		return nil, errs.ErrInvalidMaxOpenReviews
+	}
+	if dto.ReviewWindowDays != nil && *dto.ReviewWindowDays < 1 {
+		s.logger.Debug("failed to SetTeamSettings: invalid review window", "dto", dto)
+		return nil, errs.ErrInvalidReviewWindow
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	exists, err := s.teamRepo.GetTeam(ctx, tx, dto.TeamName)
+	if err != nil {
+		s.logger.Debug("failed to SetTeamSettings: error in GetTeam", "dto", dto, "err", err)
+		return nil, err
+	}
+	minReviewers := exists.MinReviewers
+	if dto.MinReviewers != nil {
+		minReviewers = *dto.MinReviewers
+	}
+	maxReviewers := exists.MaxReviewers
+	if dto.MaxReviewers != nil {
+		maxReviewers = *dto.MaxReviewers
+	}
+	if minReviewers < 0 || maxReviewers < 1 || minReviewers > maxReviewers {
+		s.logger.Debug("failed to SetTeamSettings: invalid reviewer count", "dto", dto)
+		return nil, errs.ErrInvalidReviewerCount
+	}
+	requiredApprovals := exists.RequiredApprovals
+	if dto.RequiredApprovals != nil {
+		requiredApprovals = *dto.RequiredApprovals
+	}
+	if requiredApprovals < 0 || requiredApprovals > maxReviewers {
+		s.logger.Debug("failed to SetTeamSettings: invalid required approvals", "dto", dto)
+		return nil, errs.ErrInvalidRequiredApprovals
+	}
+
+	err = s.teamRepo.UpdateTeam(ctx, tx, dto.TeamName, &update)
+	if err != nil {
+		s.logger.Debug("failed to SetTeamSettings: error in UpdateTeam", "dto", dto, "err", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		s.logger.Debug("failed to SetTeamSettings: failed commiting result", "dto", dto, "err", err)
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+	return s.GetTeam(ctx, dto.TeamName)
+}
+
+func (s *TeamService) SetFallbackTeams(
+	ctx context.Context,
+	dto entity.SetFallbackTeamsDTO,
+) (*entity.TeamDTO, error) {
+	_, err := s.teamRepo.GetTeam(ctx, s.pool, dto.TeamName)
+	if err != nil {
+		s.logger.Debug("failed to SetFallbackTeams: error in GetTeam", "dto", dto, "err", err)
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = s.setFallbackTeams(ctx, tx, dto.TeamName, dto.FallbackTeams)
+	if err != nil {
+		s.logger.Debug("failed to SetFallbackTeams", "dto", dto, "err", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		s.logger.Debug("failed to SetFallbackTeams: failed commiting result", "dto", dto, "err", err)
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+	return s.GetTeam(ctx, dto.TeamName)
+}
+
+func (s *TeamService) setFallbackTeams(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+	fallbacks []string,
+) error {
+	for i, fallback := range fallbacks {
+		if fallback == teamName || slices.Contains(fallbacks[:i], fallback) {
+			return errs.ErrInvalidFallbackTeams
+		}
+		_, err := s.teamRepo.GetTeam(ctx, db, fallback)
+		if err != nil {
+			return err
+		}
+	}
+	return s.teamRepo.SetFallbackTeams(ctx, db, teamName, fallbacks)
+}
+
+func (s *TeamService) GetCodeOwners(
+	ctx context.Context,
+	teamName string,
+) (*entity.TeamCodeOwnersDTO, error) {
+	_, err := s.teamRepo.GetTeam(ctx, s.pool, teamName)
+	if err != nil {
+		s.logger.Debug(
+			"failed to GetCodeOwners: error in GetTeam",
+			"teamName",
+			teamName,
+			"err",
+			err,
+		)
+		return nil, err
+	}
+
+	rules, err := s.teamRepo.GetCodeOwnerRules(ctx, s.pool, teamName)
+	if err != nil {
+		s.logger.Debug(
+			"failed to GetCodeOwners: error in GetCodeOwnerRules",
+			"teamName",
+			teamName,
+			"err",
+			err,
+		)
+		return nil, err
+	}
+
+	rulesDTO := make([]entity.CodeOwnerRuleDTO, len(rules))
+	for i, rule := range rules {
+		rulesDTO[i] = entity.CodeOwnerRuleDTO{
+			Pattern: rule.Pattern,
+			Users:   rule.UserIds,
+			Teams:   rule.TeamNames,
+		}
+	}
+	return &entity.TeamCodeOwnersDTO{
+		TeamName: teamName,
+		Rules:    rulesDTO,
 	}, nil
 }
+
+func (s *TeamService) SetCodeOwners(
+	ctx context.Context,
+	dto entity.TeamCodeOwnersDTO,
+) (*entity.TeamCodeOwnersDTO, error) {
+	_, err := s.teamRepo.GetTeam(ctx, s.pool, dto.TeamName)
+	if err != nil {
+		s.logger.Debug("failed to SetCodeOwners: error in GetTeam", "dto", dto, "err", err)
+		return nil, err
+	}
+
+	rules := make([]entity.CodeOwnerRule, len(dto.Rules))
+	for i, rule := range dto.Rules {
+		if _, err := compileCodeOwnerPattern(rule.Pattern); err != nil || len(rule.Pattern) > 256 {
+			s.logger.Debug("failed to SetCodeOwners: invalid pattern", "pattern", rule.Pattern)
+			return nil, errs.ErrInvalidCodeOwnerPattern
+		}
+		if len(rule.Users) == 0 && len(rule.Teams) == 0 {
+			s.logger.Debug("failed to SetCodeOwners: rule without owners", "pattern", rule.Pattern)
+			return nil, errs.ErrInvalidCodeOwnerRule
+		}
+		for _, userId := range rule.Users {
+			if _, err := s.userRepo.GetById(ctx, s.pool, userId); err != nil {
+				s.logger.Debug(
+					"failed to SetCodeOwners: error in GetById",
+					"userId",
+					userId,
+					"err",
+					err,
+				)
+				return nil, err
+			}
+		}
+		for _, teamName := range rule.Teams {
+			if _, err := s.teamRepo.GetTeam(ctx, s.pool, teamName); err != nil {
+				s.logger.Debug(
+					"failed to SetCodeOwners: error in GetTeam",
+					"teamName",
+					teamName,
+					"err",
+					err,
+				)
+				return nil, err
+			}
+		}
+		rules[i] = entity.CodeOwnerRule{
+			TeamName:  dto.TeamName,
+			Pattern:   rule.Pattern,
+			UserIds:   rule.Users,
+			TeamNames: rule.Teams,
+		}
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = s.teamRepo.SetCodeOwnerRules(ctx, tx, dto.TeamName, rules)
+	if err != nil {
+		s.logger.Debug(
+			"failed to SetCodeOwners: error in SetCodeOwnerRules",
+			"dto",
+			dto,
+			"err",
+			err,
+		)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		s.logger.Debug("failed to SetCodeOwners: failed commiting result", "dto", dto, "err", err)
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+	return s.GetCodeOwners(ctx, dto.TeamName)
+}
+
+func (s *TeamService) GetRepositories(
+	ctx context.Context,
+	teamName string,
+) (*entity.TeamRepositoriesDTO, error) {
+	_, err := s.teamRepo.GetTeam(ctx, s.pool, teamName)
+	if err != nil {
+		s.logger.Debug(
+			"failed to GetRepositories: error in GetTeam",
+			"teamName",
+			teamName,
+			"err",
+			err,
+		)
+		return nil, err
+	}
+
+	repos, err := s.teamRepo.GetRepositoriesByTeamName(ctx, s.pool, teamName)
+	if err != nil {
+		s.logger.Debug(
+			"failed to GetRepositories: error in GetRepositoriesByTeamName",
+			"teamName",
+			teamName,
+			"err",
+			err,
+		)
+		return nil, err
+	}
+
+	reposDTO := make([]entity.RepositoryDTO, len(repos))
+	for i, repo := range repos {
+		reposDTO[i] = entity.RepositoryDTO{
+			RepositoryName:      repo.RepositoryName,
+			TeamName:            repo.TeamName,
+			RouteReviewsToOwner: repo.RouteReviewsToOwner,
+		}
+	}
+	return &entity.TeamRepositoriesDTO{
+		TeamName:     teamName,
+		Repositories: reposDTO,
+	}, nil
+}
+
+// SetRepository adds a repository owned by dto.TeamName, or moves an
+// existing one to it and replaces its review routing. Existing PRs of a
+// moved repository are reviewed by the new owner from then on.
+func (s *TeamService) SetRepository(
+	ctx context.Context,
+	dto entity.RepositoryDTO,
+) (*entity.RepositoryDTO, error) {
+	// PR ids are "<repository>#<number>" and must fit 64 characters.
+	name := dto.RepositoryName
+	if strings.TrimSpace(name) == "" || len(name) > 48 || strings.Contains(name, "#") {
+		s.logger.Debug("failed to SetRepository: invalid name", "dto", dto)
+		return nil, errs.ErrInvalidRepository
+	}
+	_, err := s.teamRepo.GetTeam(ctx, s.pool, dto.TeamName)
+	if err != nil {
+		s.logger.Debug("failed to SetRepository: error in GetTeam", "dto", dto, "err", err)
+		return nil, err
+	}
+
+	err = s.teamRepo.SetRepository(ctx, s.pool, &entity.Repository{
+		RepositoryName:      dto.RepositoryName,
+		TeamName:            dto.TeamName,
+		RouteReviewsToOwner: dto.RouteReviewsToOwner,
+	})
+	if err != nil {
+		s.logger.Debug("failed to SetRepository: error in SetRepository", "dto", dto, "err", err)
+		return nil, err
+	}
+	return &dto, nil
+}
@@ -3,18 +3,22 @@ package service
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserService struct {
-	logger   *slog.Logger
-	pool     *pgxpool.Pool
-	userRepo repository.BaseUserRepository
-	prRepo   repository.BasePullRequestRepository
+	logger      *slog.Logger
+	pool        *pgxpool.Pool
+	userRepo    repository.BaseUserRepository
+	prRepo      repository.BasePullRequestRepository
+	absenceRepo repository.BaseAbsenceRepository
+	prService   BasePullRequestService
 }
 
 func NewUserService(
@@ -22,27 +26,37 @@ func NewUserService(
 	pool *pgxpool.Pool,
 	userRepo repository.BaseUserRepository,
 	prRepo repository.BasePullRequestRepository,
+	absenceRepo repository.BaseAbsenceRepository,
+	prService BasePullRequestService,
 ) BaseUserService {
 	logger := baseLogger.With("module", "userservice")
 	return &UserService{
-		logger:   logger,
-		pool:     pool,
-		userRepo: userRepo,
-		prRepo:   prRepo,
+		logger:      logger,
+		pool:        pool,
+		userRepo:    userRepo,
+		prRepo:      prRepo,
+		absenceRepo: absenceRepo,
+		prService:   prService,
 	}
 }
 
 func (s *UserService) SetIsActive(
 	ctx context.Context,
 	dto entity.SetUserIsActiveDTO,
-) (*entity.UserDTO, error) {
+) (*entity.SetUserIsActiveResponseDTO, error) {
 	exists, err := s.userRepo.GetById(ctx, s.pool, dto.UserId)
 	if err != nil {
 		s.logger.Debug("failed to SetIsActive: GetById failed", "err", err)
 		return nil, err
 	}
 
-	err = s.userRepo.UpdateUser(ctx, s.pool, exists.Id, &entity.UserUpdate{
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = s.userRepo.UpdateUser(ctx, tx, exists.Id, &entity.UserUpdate{
 		IsActive: &dto.IsActive,
 	})
 	if err != nil {
@@ -50,13 +64,130 @@ func (s *UserService) SetIsActive(
 		return nil, err
 	}
 
+	var affected []entity.AffectedPullRequestDTO
+	if !dto.IsActive {
+		affected, err = s.prService.ReassignUserReviews(ctx, tx, exists.Id)
+		if err != nil {
+			s.logger.Debug("failed to SetIsActive: ReassignUserReviews failed", "err", err)
+			return nil, err
+		}
+	}
+
+	skills, err := s.userSkills(ctx, tx, exists.Id)
+	if err != nil {
+		s.logger.Debug("failed to SetIsActive: GetSkillsByUserIds failed", "err", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+
+	return &entity.SetUserIsActiveResponseDTO{
+		UserDTO: entity.UserDTO{
+			UserId:         exists.Id,
+			Username:       exists.Username,
+			IsActive:       dto.IsActive,
+			Skills:         skills,
+			MaxOpenReviews: exists.MaxOpenReviews,
+		},
+		AffectedPullRequests: affected,
+	}, nil
+}
+
+func (s *UserService) SetSkills(
+	ctx context.Context,
+	dto entity.SetUserSkillsDTO,
+) (*entity.UserDTO, error) {
+	skills, err := normalizeTags(dto.Skills)
+	if err != nil {
+		s.logger.Debug("failed to SetSkills: invalid skills", "dto", dto, "err", err)
+		return nil, err
+	}
+
+	exists, err := s.userRepo.GetById(ctx, s.pool, dto.UserId)
+	if err != nil {
+		s.logger.Debug("failed to SetSkills: GetById failed", "err", err)
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, errs.ErrInternal("error begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = s.userRepo.SetSkills(ctx, tx, exists.Id, skills)
+	if err != nil {
+		s.logger.Debug("failed to SetSkills: SetSkills failed", "err", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, errs.ErrInternal("error commit transaction", err)
+	}
+
 	return &entity.UserDTO{
-		UserId:   exists.Id,
-		Username: exists.Username,
-		IsActive: dto.IsActive,
+		UserId:         exists.Id,
+		Username:       exists.Username,
+		IsActive:       exists.IsActive,
+		Skills:         skills,
+		MaxOpenReviews: exists.MaxOpenReviews,
 	}, nil
 }
 
+func (s *UserService) SetMaxOpenReviews(
+	ctx context.Context,
+	dto entity.SetUserMaxOpenReviewsDTO,
+) (*entity.UserDTO, error) {
+	if dto.MaxOpenReviews != nil && *dto.MaxOpenReviews < 0 {
+		s.logger.Debug("failed to SetMaxOpenReviews: invalid limit", "dto", dto)
+		return nil, errs.ErrInvalidMaxOpenReviews
+	}
+
+	exists, err := s.userRepo.GetById(ctx, s.pool, dto.UserId)
+	if err != nil {
+		s.logger.Debug("failed to SetMaxOpenReviews: GetById failed", "err", err)
+		return nil, err
+	}
+
+	err = s.userRepo.SetMaxOpenReviews(ctx, s.pool, exists.Id, dto.MaxOpenReviews)
+	if err != nil {
+		s.logger.Debug("failed to SetMaxOpenReviews: SetMaxOpenReviews failed", "err", err)
+		return nil, err
+	}
+	skills, err := s.userSkills(ctx, s.pool, exists.Id)
+	if err != nil {
+		s.logger.Debug("failed to SetMaxOpenReviews: GetSkillsByUserIds failed", "err", err)
+		return nil, err
+	}
+
+	return &entity.UserDTO{
+		UserId:         exists.Id,
+		Username:       exists.Username,
+		IsActive:       exists.IsActive,
+		Skills:         skills,
+		MaxOpenReviews: dto.MaxOpenReviews,
+	}, nil
+}
+
+// userSkills returns the skills of userId for responses that show the user.
+func (s *UserService) userSkills(
+	ctx context.Context,
+	db repository.Querier,
+	userId string,
+) ([]string, error) {
+	userSkills, err := s.userRepo.GetSkillsByUserIds(ctx, db, []string{userId})
+	if err != nil {
+		return nil, err
+	}
+	var skills []string
+	for _, skill := range userSkills {
+		skills = append(skills, skill.Skill)
+	}
+	return skills, nil
+}
+
 func (s *UserService) GetReview(
 	ctx context.Context,
 	userId string,
@@ -75,11 +206,20 @@ func (s *UserService) GetReview(
 
 	prsDTO := make([]entity.PullRequestDTO, len(prs))
 	for i, pr := range prs {
+		labels, err := s.prRepo.GetPullRequestLabels(ctx, s.pool, pr.Id)
+		if err != nil {
+			s.logger.Debug("failed to GetReview: GetPullRequestLabels failed", "err", err)
+			return nil, err
+		}
 		prsDTO[i] = entity.PullRequestDTO{
 			PullRequestId:   pr.Id,
 			PullRequestName: pr.PullRequestName,
+			Description:     pr.Description,
 			AuthorId:        pr.AuthorId,
 			Status:          pr.Status,
+			Labels:          labels,
+			RepositoryName:  pr.RepositoryName,
+			Number:          pr.Number,
 		}
 	}
 	return &entity.UserPullRequestsDTO{
@@ -87,3 +227,123 @@ func (s *UserService) GetReview(
 		PullRequests: prsDTO,
 	}, nil
 }
+
+func (s *UserService) AddAbsence(
+	ctx context.Context,
+	dto entity.AbsenceDTO,
+) (*entity.AbsenceDTO, error) {
+	absence, err := absenceFromDTO(dto)
+	if err != nil {
+		s.logger.Debug("failed to AddAbsence: invalid absence", "dto", dto, "err", err)
+		return nil, err
+	}
+
+	_, err = s.userRepo.GetById(ctx, s.pool, dto.UserId)
+	if err != nil {
+		s.logger.Debug("failed to AddAbsence: GetById failed", "err", err)
+		return nil, err
+	}
+
+	err = s.absenceRepo.AddAbsence(ctx, s.pool, absence)
+	if err != nil {
+		s.logger.Debug("failed to AddAbsence: AddAbsence failed", "err", err)
+		return nil, err
+	}
+
+	res := absenceToDTO(absence)
+	return &res, nil
+}
+
+func (s *UserService) GetAbsences(
+	ctx context.Context,
+	userId string,
+) (*entity.UserAbsencesDTO, error) {
+	exists, err := s.userRepo.GetById(ctx, s.pool, userId)
+	if err != nil {
+		s.logger.Debug("failed to GetAbsences: GetById failed", "err", err)
+		return nil, err
+	}
+
+	absences, err := s.absenceRepo.GetAbsencesByUserId(ctx, s.pool, exists.Id)
+	if err != nil {
+		s.logger.Debug("failed to GetAbsences: GetAbsencesByUserId failed", "err", err)
+		return nil, err
+	}
+
+	result := &entity.UserAbsencesDTO{
+		UserId:   exists.Id,
+		Absences: make([]entity.AbsenceDTO, len(absences)),
+	}
+	for i := range absences {
+		result.Absences[i] = absenceToDTO(&absences[i])
+	}
+	return result, nil
+}
+
+func (s *UserService) CancelAbsence(
+	ctx context.Context,
+	dto entity.CancelAbsenceDTO,
+) (*entity.UserAbsencesDTO, error) {
+	err := s.absenceRepo.RemoveAbsence(ctx, s.pool, dto.UserId, dto.AbsenceId)
+	if err != nil {
+		s.logger.Debug("failed to CancelAbsence: RemoveAbsence failed", "err", err)
+		return nil, err
+	}
+	return s.GetAbsences(ctx, dto.UserId)
+}
+
+func absenceFromDTO(dto entity.AbsenceDTO) (*entity.Absence, error) {
+	if len(dto.Reason) > 256 {
+		return nil, errs.ErrInvalidAbsence
+	}
+	absence := &entity.Absence{
+		UserId: dto.UserId,
+		Kind:   dto.Kind,
+		Reason: dto.Reason,
+	}
+
+	switch dto.Kind {
+	case entity.AbsenceVacation:
+		if dto.From == nil || dto.To == nil || dto.Weekday != nil {
+			return nil, errs.ErrInvalidAbsence
+		}
+		from, err := time.Parse(time.RFC3339, *dto.From)
+		if err != nil {
+			return nil, errs.ErrInvalidAbsence
+		}
+		to, err := time.Parse(time.RFC3339, *dto.To)
+		if err != nil || !from.Before(to) {
+			return nil, errs.ErrInvalidAbsence
+		}
+		absence.StartsAt = &from
+		absence.EndsAt = &to
+	case entity.AbsenceDayOff:
+		if dto.Weekday == nil || *dto.Weekday < 1 || *dto.Weekday > 7 ||
+			dto.From != nil || dto.To != nil {
+			return nil, errs.ErrInvalidAbsence
+		}
+		absence.Weekday = dto.Weekday
+	default:
+		return nil, errs.ErrInvalidAbsence
+	}
+	return absence, nil
+}
+
+func absenceToDTO(absence *entity.Absence) entity.AbsenceDTO {
+	dto := entity.AbsenceDTO{
+		AbsenceId: absence.Id,
+		UserId:    absence.UserId,
+		Kind:      absence.Kind,
+		Weekday:   absence.Weekday,
+		Reason:    absence.Reason,
+	}
+	if absence.StartsAt != nil {
+		from := absence.StartsAt.Format(time.RFC3339)
+		dto.From = &from
+	}
+	if absence.EndsAt != nil {
+		to := absence.EndsAt.Format(time.RFC3339)
+		dto.To = &to
+	}
+	return dto
+}
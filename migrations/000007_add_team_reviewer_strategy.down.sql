@@ -0,0 +1 @@
+ALTER TABLE teams DROP COLUMN reviewer_strategy;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN reviewer_strategy varchar(32) NOT NULL DEFAULT 'random';
\ No newline at end of file
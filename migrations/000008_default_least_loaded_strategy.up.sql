@@ -0,0 +1,2 @@
+ALTER TABLE teams ALTER COLUMN reviewer_strategy SET DEFAULT 'least_loaded';
+UPDATE teams SET reviewer_strategy = 'least_loaded' WHERE reviewer_strategy = 'random';
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_reviewers_range_check;
+ALTER TABLE teams DROP COLUMN max_reviewers;
+ALTER TABLE teams DROP COLUMN min_reviewers;
\ No newline at end of file
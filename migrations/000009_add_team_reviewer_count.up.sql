@@ -0,0 +1,4 @@
+ALTER TABLE teams ADD COLUMN min_reviewers int NOT NULL DEFAULT 0;
+ALTER TABLE teams ADD COLUMN max_reviewers int NOT NULL DEFAULT 2;
+ALTER TABLE teams ADD CONSTRAINT teams_reviewers_range_check
+    CHECK (min_reviewers >= 0 AND max_reviewers >= 1 AND min_reviewers <= max_reviewers);
\ No newline at end of file
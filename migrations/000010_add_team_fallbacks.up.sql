@@ -0,0 +1,12 @@
+CREATE TABLE team_fallbacks (
+    team_name varchar(128) NOT NULL,
+    fallback_team_name varchar(128) NOT NULL,
+    position int NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name),
+    CHECK (team_name <> fallback_team_name)
+);
+
+CREATE INDEX idx_team_fallbacks_team_name ON team_fallbacks (team_name, position);
+
+ALTER TABLE team_fallbacks ADD CONSTRAINT FK_team_fallbacks_1 FOREIGN KEY (team_name) REFERENCES teams (name);
+ALTER TABLE team_fallbacks ADD CONSTRAINT FK_team_fallbacks_2 FOREIGN KEY (fallback_team_name) REFERENCES teams (name);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pull_requests_paths;
+DROP TABLE IF EXISTS code_owner_rules;
\ No newline at end of file
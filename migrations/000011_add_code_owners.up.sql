@@ -0,0 +1,17 @@
+CREATE TABLE code_owner_rules (
+    team_name varchar(128) NOT NULL,
+    position int NOT NULL,
+    pattern varchar(256) NOT NULL,
+    user_ids varchar(64)[] NOT NULL DEFAULT '{}',
+    team_names varchar(128)[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY (team_name, position)
+);
+
+CREATE TABLE pull_requests_paths (
+    pr_id varchar(64) NOT NULL,
+    path varchar(1024) NOT NULL,
+    PRIMARY KEY (pr_id, path)
+);
+
+ALTER TABLE code_owner_rules ADD CONSTRAINT FK_code_owner_rules_1 FOREIGN KEY (team_name) REFERENCES teams (name);
+ALTER TABLE pull_requests_paths ADD CONSTRAINT FK_pull_requests_paths_1 FOREIGN KEY (pr_id) REFERENCES pull_requests (id);
\ No newline at end of file
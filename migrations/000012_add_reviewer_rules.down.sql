@@ -0,0 +1 @@
+DROP TABLE IF EXISTS reviewer_rules;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE reviewer_rules (
+    author_id varchar(64) NOT NULL,
+    reviewer_id varchar(64) NOT NULL,
+    kind varchar(16) NOT NULL,
+    PRIMARY KEY (author_id, reviewer_id),
+    CHECK (author_id <> reviewer_id)
+);
+
+ALTER TABLE reviewer_rules ADD CONSTRAINT FK_reviewer_rules_1 FOREIGN KEY (author_id) REFERENCES users (id);
+ALTER TABLE reviewer_rules ADD CONSTRAINT FK_reviewer_rules_2 FOREIGN KEY (reviewer_id) REFERENCES users (id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pull_requests_assignments;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE pull_requests_assignments (
+    id bigserial NOT NULL,
+    pr_id varchar(64) NOT NULL,
+    kind varchar(16) NOT NULL,
+    seed bigint NOT NULL,
+    steps jsonb NOT NULL,
+    reviewers varchar(64)[] NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_pull_requests_assignments_pr_id ON pull_requests_assignments (pr_id);
+
+ALTER TABLE pull_requests_assignments ADD CONSTRAINT FK_pull_requests_assignments_1 FOREIGN KEY (pr_id) REFERENCES pull_requests (id);
\ No newline at end of file
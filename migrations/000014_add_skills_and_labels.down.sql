@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pull_requests_labels;
+DROP TABLE IF EXISTS users_skills;
\ No newline at end of file
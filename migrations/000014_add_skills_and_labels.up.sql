@@ -0,0 +1,16 @@
+CREATE TABLE users_skills (
+    user_id varchar(64) NOT NULL,
+    skill varchar(64) NOT NULL,
+    PRIMARY KEY (user_id, skill)
+);
+
+CREATE TABLE pull_requests_labels (
+    pr_id varchar(64) NOT NULL,
+    label varchar(64) NOT NULL,
+    PRIMARY KEY (pr_id, label)
+);
+
+CREATE INDEX idx_users_skills_skill ON users_skills (skill);
+
+ALTER TABLE users_skills ADD CONSTRAINT FK_users_skills_1 FOREIGN KEY (user_id) REFERENCES users (id);
+ALTER TABLE pull_requests_labels ADD CONSTRAINT FK_pull_requests_labels_1 FOREIGN KEY (pr_id) REFERENCES pull_requests (id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS users_absences;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE users_absences (
+    id bigserial NOT NULL,
+    user_id varchar(64) NOT NULL,
+    kind varchar(16) NOT NULL,
+    starts_at timestamptz,
+    ends_at timestamptz,
+    weekday int,
+    reason varchar(256) NOT NULL DEFAULT '',
+    PRIMARY KEY (id),
+    CONSTRAINT users_absences_kind_check CHECK (
+        (kind = 'VACATION' AND starts_at < ends_at AND weekday IS NULL) OR
+        (kind = 'DAY_OFF' AND weekday BETWEEN 1 AND 7 AND starts_at IS NULL AND ends_at IS NULL)
+    )
+);
+
+CREATE INDEX idx_users_absences_user_id ON users_absences (user_id);
+
+ALTER TABLE users_absences ADD CONSTRAINT FK_users_absences_1 FOREIGN KEY (user_id) REFERENCES users (id);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
+ALTER TABLE teams DROP COLUMN IF EXISTS max_open_reviews;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE teams ADD COLUMN max_open_reviews int;
+ALTER TABLE teams ADD CONSTRAINT teams_max_open_reviews_check CHECK (max_open_reviews >= 0);
+
+ALTER TABLE users ADD COLUMN max_open_reviews int;
+ALTER TABLE users ADD CONSTRAINT users_max_open_reviews_check CHECK (max_open_reviews >= 0);
\ No newline at end of file
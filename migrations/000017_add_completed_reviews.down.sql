@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS completed_reviews;
+ALTER TABLE teams DROP COLUMN IF EXISTS review_window_days;
+ALTER TABLE pull_requests_users DROP COLUMN IF EXISTS assigned_at;
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE pull_requests_users ADD COLUMN assigned_at timestamptz NOT NULL DEFAULT now();
+
+ALTER TABLE teams ADD COLUMN review_window_days int NOT NULL DEFAULT 14;
+ALTER TABLE teams ADD CONSTRAINT teams_review_window_days_check CHECK (review_window_days > 0);
+
+CREATE TABLE completed_reviews (
+    id bigserial NOT NULL,
+    pr_id varchar(64) NOT NULL,
+    user_id varchar(64) NOT NULL,
+    assigned_at timestamptz NOT NULL,
+    completed_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_completed_reviews_user_id_completed_at ON completed_reviews (user_id, completed_at);
+
+ALTER TABLE completed_reviews ADD CONSTRAINT FK_completed_reviews_1 FOREIGN KEY (pr_id) REFERENCES pull_requests (id);
+ALTER TABLE completed_reviews ADD CONSTRAINT FK_completed_reviews_2 FOREIGN KEY (user_id) REFERENCES users (id);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pull_requests_reviews;
+ALTER TABLE teams DROP COLUMN IF EXISTS required_approvals;
\ No newline at end of file
@@ -0,0 +1,17 @@
+ALTER TABLE teams ADD COLUMN required_approvals int NOT NULL DEFAULT 0;
+ALTER TABLE teams ADD CONSTRAINT teams_required_approvals_check CHECK (required_approvals >= 0);
+
+CREATE TABLE pull_requests_reviews (
+    pr_id varchar(64) NOT NULL,
+    user_id varchar(64) NOT NULL,
+    decision varchar(32) NOT NULL,
+    comment varchar(4096) NOT NULL DEFAULT '',
+    submitted_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (pr_id, user_id),
+    CONSTRAINT pull_requests_reviews_decision_check CHECK (
+        decision IN ('APPROVED', 'CHANGES_REQUESTED')
+    )
+);
+
+ALTER TABLE pull_requests_reviews ADD CONSTRAINT FK_pull_requests_reviews_1 FOREIGN KEY (pr_id) REFERENCES pull_requests (id);
+ALTER TABLE pull_requests_reviews ADD CONSTRAINT FK_pull_requests_reviews_2 FOREIGN KEY (user_id) REFERENCES users (id);
\ No newline at end of file
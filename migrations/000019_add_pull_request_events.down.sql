@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pull_request_events;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS merged_at;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS created_at;
\ No newline at end of file
@@ -0,0 +1,25 @@
+ALTER TABLE pull_requests ADD COLUMN created_at timestamptz NOT NULL DEFAULT now();
+ALTER TABLE pull_requests ADD COLUMN merged_at timestamptz;
+UPDATE pull_requests SET merged_at = updated_at WHERE status = 'MERGED';
+
+CREATE TABLE pull_request_events (
+    id bigserial NOT NULL,
+    pr_id varchar(64) NOT NULL,
+    kind varchar(32) NOT NULL,
+    actor_id varchar(64),
+    user_id varchar(64),
+    from_status varchar(128),
+    to_status varchar(128),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (id),
+    CONSTRAINT pull_request_events_kind_check CHECK (
+        (kind = 'STATUS_CHANGED' AND to_status IS NOT NULL AND user_id IS NULL) OR
+        (kind IN ('REVIEWER_ADDED', 'REVIEWER_REMOVED') AND user_id IS NOT NULL AND to_status IS NULL)
+    )
+);
+
+CREATE INDEX idx_pull_request_events_pr_id ON pull_request_events (pr_id);
+
+ALTER TABLE pull_request_events ADD CONSTRAINT FK_pull_request_events_1 FOREIGN KEY (pr_id) REFERENCES pull_requests (id);
+ALTER TABLE pull_request_events ADD CONSTRAINT FK_pull_request_events_2 FOREIGN KEY (actor_id) REFERENCES users (id);
+ALTER TABLE pull_request_events ADD CONSTRAINT FK_pull_request_events_3 FOREIGN KEY (user_id) REFERENCES users (id);
\ No newline at end of file
@@ -0,0 +1,9 @@
+DELETE FROM pull_request_events WHERE kind = 'UPDATED';
+ALTER TABLE pull_request_events DROP CONSTRAINT pull_request_events_kind_check;
+ALTER TABLE pull_request_events ADD CONSTRAINT pull_request_events_kind_check CHECK (
+    (kind = 'STATUS_CHANGED' AND to_status IS NOT NULL AND user_id IS NULL) OR
+    (kind IN ('REVIEWER_ADDED', 'REVIEWER_REMOVED') AND user_id IS NOT NULL AND to_status IS NULL)
+);
+ALTER TABLE pull_request_events DROP COLUMN IF EXISTS fields;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS description;
\ No newline at end of file
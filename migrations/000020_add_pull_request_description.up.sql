@@ -0,0 +1,9 @@
+ALTER TABLE pull_requests ADD COLUMN description varchar(4096) NOT NULL DEFAULT '';
+
+ALTER TABLE pull_request_events ADD COLUMN fields varchar(32)[];
+ALTER TABLE pull_request_events DROP CONSTRAINT pull_request_events_kind_check;
+ALTER TABLE pull_request_events ADD CONSTRAINT pull_request_events_kind_check CHECK (
+    (kind = 'STATUS_CHANGED' AND to_status IS NOT NULL AND user_id IS NULL) OR
+    (kind IN ('REVIEWER_ADDED', 'REVIEWER_REMOVED') AND user_id IS NOT NULL AND to_status IS NULL) OR
+    (kind = 'UPDATED' AND fields IS NOT NULL AND user_id IS NULL AND to_status IS NULL)
+);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pull_requests_labels_label;
+DROP INDEX IF EXISTS idx_pull_requests_merged_at;
+DROP INDEX IF EXISTS idx_pull_requests_status;
+DROP INDEX IF EXISTS idx_pull_requests_author_id;
+DROP INDEX IF EXISTS idx_pull_requests_name;
+DROP INDEX IF EXISTS idx_pull_requests_created_at;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE INDEX idx_pull_requests_created_at ON pull_requests (created_at, id);
+CREATE INDEX idx_pull_requests_name ON pull_requests (name, id);
+CREATE INDEX idx_pull_requests_author_id ON pull_requests (author_id);
+CREATE INDEX idx_pull_requests_status ON pull_requests (status);
+CREATE INDEX idx_pull_requests_merged_at ON pull_requests (merged_at) WHERE merged_at IS NOT NULL;
+CREATE INDEX idx_pull_requests_labels_label ON pull_requests_labels (label);
\ No newline at end of file
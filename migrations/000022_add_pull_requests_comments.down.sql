@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pull_requests_comments;
+ALTER TABLE teams DROP COLUMN IF EXISTS require_resolved_comments;
\ No newline at end of file
@@ -0,0 +1,23 @@
+ALTER TABLE teams ADD COLUMN require_resolved_comments boolean NOT NULL DEFAULT false;
+
+CREATE TABLE pull_requests_comments (
+    id bigserial NOT NULL,
+    pr_id varchar(64) NOT NULL,
+    parent_id bigint,
+    author_id varchar(64) NOT NULL,
+    body varchar(4096) NOT NULL,
+    resolved_by varchar(64),
+    resolved_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (id),
+    CONSTRAINT pull_requests_comments_resolved_check CHECK (
+        parent_id IS NULL OR (resolved_by IS NULL AND resolved_at IS NULL)
+    )
+);
+
+CREATE INDEX idx_pull_requests_comments_pr_id ON pull_requests_comments (pr_id);
+
+ALTER TABLE pull_requests_comments ADD CONSTRAINT FK_pull_requests_comments_1 FOREIGN KEY (pr_id) REFERENCES pull_requests (id);
+ALTER TABLE pull_requests_comments ADD CONSTRAINT FK_pull_requests_comments_2 FOREIGN KEY (parent_id) REFERENCES pull_requests_comments (id);
+ALTER TABLE pull_requests_comments ADD CONSTRAINT FK_pull_requests_comments_3 FOREIGN KEY (author_id) REFERENCES users (id);
+ALTER TABLE pull_requests_comments ADD CONSTRAINT FK_pull_requests_comments_4 FOREIGN KEY (resolved_by) REFERENCES users (id);
\ No newline at end of file
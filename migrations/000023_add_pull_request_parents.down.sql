@@ -0,0 +1 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
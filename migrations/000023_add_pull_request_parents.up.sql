@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests ADD COLUMN parent_id varchar(64);
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_parent_id_check CHECK (parent_id <> id);
+
+CREATE INDEX idx_pull_requests_parent_id ON pull_requests (parent_id);
+
+ALTER TABLE pull_requests ADD CONSTRAINT FK_pull_requests_1 FOREIGN KEY (parent_id) REFERENCES pull_requests (id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS number;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository_name;
+DROP TABLE IF EXISTS repositories;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE repositories (
+    name varchar(48) NOT NULL,
+    team_name varchar(128) NOT NULL,
+    route_reviews_to_owner boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (name)
+);
+
+CREATE INDEX idx_repositories_team_name ON repositories (team_name);
+
+ALTER TABLE repositories ADD CONSTRAINT FK_repositories_1 FOREIGN KEY (team_name) REFERENCES teams (name);
+
+ALTER TABLE pull_requests ADD COLUMN repository_name varchar(48);
+ALTER TABLE pull_requests ADD COLUMN number integer;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_number_check CHECK (
+    (repository_name IS NULL) = (number IS NULL) AND number > 0
+);
+
+CREATE UNIQUE INDEX idx_pull_requests_repository_name_number ON pull_requests (repository_name, number);
+
+ALTER TABLE pull_requests ADD CONSTRAINT FK_pull_requests_2 FOREIGN KEY (repository_name) REFERENCES repositories (name);
\ No newline at end of file
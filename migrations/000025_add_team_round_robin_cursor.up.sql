@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN round_robin_cursor varchar(64) NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests ADD COLUMN closed_at timestamptz;
+UPDATE pull_requests SET closed_at = updated_at WHERE status = 'CLOSED';
\ No newline at end of file
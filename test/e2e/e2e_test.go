@@ -5,9 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math/rand/v2"
 	"os"
+	"reflect"
 	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
 	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
@@ -25,6 +30,7 @@ var (
 	userService service.BaseUserService
 	teamService service.BaseTeamService
 	prService   service.BasePullRequestService
+	ruleService service.BaseReviewerRuleService
 )
 
 func TestMain(m *testing.M) {
@@ -57,10 +63,27 @@ func TestMain(m *testing.M) {
 	prRepo := postgres.NewPostgresPullRequestRepository(logger)
 	userRepo := postgres.NewPostgresUserRepository(logger)
 	teamRepo := postgres.NewPostgresTeamRepository(logger)
+	ruleRepo := postgres.NewPostgresReviewerRuleRepository(logger)
+	assignmentRepo := postgres.NewPostgresAssignmentRepository(logger)
+	reviewRepo := postgres.NewPostgresReviewRepository(logger)
+	commentRepo := postgres.NewPostgresCommentRepository(logger)
+	absenceRepo := postgres.NewPostgresAbsenceRepository(logger)
 
-	prService = service.NewPullRequestService(logger, pool, prRepo, userRepo)
-	userService = service.NewUserService(logger, pool, userRepo, prRepo)
+	prService = service.NewPullRequestService(
+		logger,
+		pool,
+		prRepo,
+		userRepo,
+		teamRepo,
+		ruleRepo,
+		assignmentRepo,
+		reviewRepo,
+		commentRepo,
+		rand.NewPCG(1, 2),
+	)
+	userService = service.NewUserService(logger, pool, userRepo, prRepo, absenceRepo, prService)
 	teamService = service.NewTeamService(logger, pool, userRepo, teamRepo)
+	ruleService = service.NewReviewerRuleService(logger, pool, ruleRepo, userRepo)
 
 	_, err = pool.Exec(globalCtx, "TRUNCATE TABLE pull_requests_users, pull_requests, users, teams RESTART IDENTITY CASCADE")
 	if err != nil {
@@ -211,6 +234,111 @@ func TestCreatePullRequest(t *testing.T) {
 	})
 }
 
+func TestCreatePullRequestLeastLoaded(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 5)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	first, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+
+	second, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr2",
+		PullRequestName: "pr2",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+
+	for _, r := range second.PullRequest.AssignedReviewers {
+		if slices.Contains(first.PullRequest.AssignedReviewers, r) {
+			t.Fatalf("Reviewer %s expected not to be assigned twice while others are free", r)
+		}
+	}
+}
+
+func TestLeastLoadedMigration(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 5)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	// Teams created before the migration have the random strategy of 000007.
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:         "team1",
+		ReviewerStrategy: entity.StrategyRandom,
+		Members:          users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	migration, err := os.ReadFile("../../migrations/000008_default_least_loaded_strategy.up.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	_, err = pool.Exec(ctx, string(migration))
+	if err != nil {
+		t.Fatalf("failed to apply migration: %v", err)
+	}
+
+	team, err := teamService.GetTeam(ctx, "team1")
+	if err != nil {
+		t.Fatalf("GetTeam should succeed, got: %v", err)
+	}
+	if team.ReviewerStrategy != entity.StrategyLeastLoaded {
+		t.Fatalf("ReviewerStrategy expected %s, got: %s", entity.StrategyLeastLoaded, team.ReviewerStrategy)
+	}
+
+	first, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	second, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr2",
+		PullRequestName: "pr2",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	for _, r := range second.PullRequest.AssignedReviewers {
+		if slices.Contains(first.PullRequest.AssignedReviewers, r) {
+			t.Fatalf("Reviewer %s expected not to be assigned twice while others are free", r)
+		}
+	}
+}
+
 func TestReassignPullRequest(t *testing.T) {
 	t.Run("after merge", func(t *testing.T) {
 		ctx := setupTest(t)
@@ -523,7 +651,7 @@ func TestAddTeam(t *testing.T) {
 			t.Fatalf("GetTeam should succeed, got: %v", err)
 		}
 
-		if !slices.Equal(res.Members, resSecondTime.Team.Members) {
+		if !reflect.DeepEqual(res.Members, resSecondTime.Team.Members) {
 			t.Fatal("Team members in `team2` expected to be same as in resSecondTime")
 		}
 
@@ -572,7 +700,7 @@ func TestAddTeam(t *testing.T) {
 			t.Fatalf("GetTeam should succeed, got: %v", err)
 		}
 
-		if !slices.Equal(res.Members, users) {
+		if !reflect.DeepEqual(res.Members, users) {
 			t.Fatal("Team members in `team2` expected to be same as in users")
 		}
 
@@ -585,3 +713,2892 @@ func TestAddTeam(t *testing.T) {
 		}
 	})
 }
+
+func TestReviewerStrategy(t *testing.T) {
+	t.Run("Unknown strategy", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:         "team1",
+			ReviewerStrategy: "unknown",
+		})
+		if !errors.Is(err, errs.ErrUnknownReviewerStrategy) {
+			t.Fatalf("AddTeam expected ErrUnknownReviewerStrategy, got: %v", err)
+		}
+	})
+	t.Run("Round robin", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 5)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:         "team1",
+			ReviewerStrategy: entity.StrategyRoundRobin,
+			Members:          users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		team, err := teamService.GetTeam(ctx, "team1")
+		if err != nil {
+			t.Fatalf("GetTeam should succeed, got: %v", err)
+		}
+		if team.ReviewerStrategy != entity.StrategyRoundRobin {
+			t.Fatalf("ReviewerStrategy expected %s, got: %s", entity.StrategyRoundRobin, team.ReviewerStrategy)
+		}
+
+		seen := make(map[string]int)
+		for i := range 2 {
+			res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+				PullRequestId:   fmt.Sprintf("pr%d", i),
+				PullRequestName: fmt.Sprintf("pr%d", i),
+				AuthorId:        "u0",
+			})
+			if err != nil {
+				t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+			}
+			for _, r := range res.PullRequest.AssignedReviewers {
+				seen[r]++
+			}
+		}
+		if len(seen) != 4 {
+			t.Fatalf("Round robin expected to cover 4 reviewers, got: %v", seen)
+		}
+
+		var cursor string
+		err = pool.QueryRow(ctx, "SELECT round_robin_cursor FROM teams WHERE name = $1", "team1").
+			Scan(&cursor)
+		if err != nil {
+			t.Fatalf("failed to read round robin cursor: %v", err)
+		}
+		if cursor != "u4" {
+			t.Fatalf("Round robin cursor expected to be stored as u4, got: %q", cursor)
+		}
+	})
+	t.Run("Change strategy", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "team1",
+			Members:  []entity.UserDTO{{UserId: "u0", Username: "user0", IsActive: true}},
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		unknown := "unknown"
+		_, err = teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+			TeamName:         "team1",
+			ReviewerStrategy: &unknown,
+		})
+		if !errors.Is(err, errs.ErrUnknownReviewerStrategy) {
+			t.Fatalf("SetTeamSettings expected ErrUnknownReviewerStrategy, got: %v", err)
+		}
+		_, err = teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{TeamName: "team1"})
+		if !errors.Is(err, errs.ErrEmptyTeamUpdate) {
+			t.Fatalf("SetTeamSettings expected ErrEmptyTeamUpdate, got: %v", err)
+		}
+
+		strategy := entity.StrategyRoundRobin
+		_, err = teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+			TeamName:         "team2",
+			ReviewerStrategy: &strategy,
+		})
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("SetTeamSettings expected ErrBaseNotFound, got: %v", err)
+		}
+		team, err := teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+			TeamName:         "team1",
+			ReviewerStrategy: &strategy,
+		})
+		if err != nil {
+			t.Fatalf("SetTeamSettings should succeed, got: %v", err)
+		}
+		if team.ReviewerStrategy != strategy {
+			t.Fatalf("ReviewerStrategy expected %s, got: %s", strategy, team.ReviewerStrategy)
+		}
+	})
+}
+
+func TestReviewerCount(t *testing.T) {
+	t.Run("Invalid range", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		minReviewers, maxReviewers := 3, 1
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:     "team1",
+			MinReviewers: &minReviewers,
+			MaxReviewers: &maxReviewers,
+		})
+		if !errors.Is(err, errs.ErrInvalidReviewerCount) {
+			t.Fatalf("AddTeam expected ErrInvalidReviewerCount, got: %v", err)
+		}
+	})
+	t.Run("Max reviewers", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 10)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		maxReviewers := 3
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:     "team1",
+			MaxReviewers: &maxReviewers,
+			Members:      users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		if len(res.PullRequest.AssignedReviewers) != 3 {
+			t.Fatalf("AssignedReviewers expected 3, got: %d", len(res.PullRequest.AssignedReviewers))
+		}
+	})
+	t.Run("Min reviewers not satisfied", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 2)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		minReviewers := 2
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:     "team1",
+			MinReviewers: &minReviewers,
+			Members:      users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if !errors.Is(err, errs.ErrNotEnoughReviewers) {
+			t.Fatalf("CreatePullRequest expected ErrNotEnoughReviewers, got: %v", err)
+		}
+	})
+	t.Run("Change counts", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 10)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "team1",
+			Members:  users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		minReviewers := 4
+		_, err = teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+			TeamName:     "team1",
+			MinReviewers: &minReviewers,
+		})
+		if !errors.Is(err, errs.ErrInvalidReviewerCount) {
+			t.Fatalf("SetTeamSettings expected ErrInvalidReviewerCount, got: %v", err)
+		}
+
+		maxReviewers := 3
+		team, err := teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+			TeamName:     "team1",
+			MaxReviewers: &maxReviewers,
+		})
+		if err != nil {
+			t.Fatalf("SetTeamSettings should succeed, got: %v", err)
+		}
+		if *team.MaxReviewers != maxReviewers {
+			t.Fatalf("MaxReviewers expected %d, got: %d", maxReviewers, *team.MaxReviewers)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		if len(res.PullRequest.AssignedReviewers) != 3 {
+			t.Fatalf("AssignedReviewers expected 3, got: %d", len(res.PullRequest.AssignedReviewers))
+		}
+	})
+}
+
+func TestFallbackTeams(t *testing.T) {
+	t.Run("Self fallback", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:      "team1",
+			FallbackTeams: []string{"team1"},
+			Members:       []entity.UserDTO{{UserId: "u0", Username: "user0", IsActive: true}},
+		})
+		if !errors.Is(err, errs.ErrInvalidFallbackTeams) {
+			t.Fatalf("AddTeam expected ErrInvalidFallbackTeams, got: %v", err)
+		}
+	})
+	t.Run("Reviewers from fallback team", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "partner",
+			Members: []entity.UserDTO{
+				{UserId: "p0", Username: "partner0", IsActive: true},
+				{UserId: "p1", Username: "partner1", IsActive: true},
+			},
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+		_, err = teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:      "team1",
+			FallbackTeams: []string{"partner"},
+			Members: []entity.UserDTO{
+				{UserId: "u0", Username: "user0", IsActive: true},
+				{UserId: "u1", Username: "user1", IsActive: true},
+			},
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		team, err := teamService.GetTeam(ctx, "team1")
+		if err != nil {
+			t.Fatalf("GetTeam should succeed, got: %v", err)
+		}
+		if !slices.Equal(team.FallbackTeams, []string{"partner"}) {
+			t.Fatalf("FallbackTeams expected [partner], got: %v", team.FallbackTeams)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		if len(res.PullRequest.AssignedReviewers) != 2 {
+			t.Fatalf("AssignedReviewers expected 2, got: %d", len(res.PullRequest.AssignedReviewers))
+		}
+		if !slices.Contains(res.PullRequest.AssignedReviewers, "u1") {
+			t.Fatalf("AssignedReviewers expected to contain u1, got: %v", res.PullRequest.AssignedReviewers)
+		}
+		if len(res.FallbackReviewers) != 1 || res.FallbackReviewers[0].TeamName != "partner" {
+			t.Fatalf("FallbackReviewers expected one reviewer from partner, got: %v", res.FallbackReviewers)
+		}
+
+		res, err = prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+			PullRequestId: "pr1",
+			OldReviewerId: "u1",
+		})
+		if err != nil {
+			t.Fatalf("ReassignPullRequest should succeed, got: %v", err)
+		}
+		if len(res.FallbackReviewers) != 1 || res.FallbackReviewers[0].TeamName != "partner" {
+			t.Fatalf("FallbackReviewers expected one reviewer from partner, got: %v", res.FallbackReviewers)
+		}
+	})
+	t.Run("Set fallback teams", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		for i, name := range []string{"team1", "first", "second"} {
+			_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+				TeamName: name,
+				Members: []entity.UserDTO{{
+					UserId:   fmt.Sprintf("u%d", i),
+					Username: fmt.Sprintf("user%d", i),
+					IsActive: true,
+				}},
+			})
+			if err != nil {
+				t.Fatalf("AddTeam should succeed, got: %v", err)
+			}
+		}
+
+		team, err := teamService.SetFallbackTeams(ctx, entity.SetFallbackTeamsDTO{
+			TeamName:      "team1",
+			FallbackTeams: []string{"second", "first"},
+		})
+		if err != nil {
+			t.Fatalf("SetFallbackTeams should succeed, got: %v", err)
+		}
+		if !slices.Equal(team.FallbackTeams, []string{"second", "first"}) {
+			t.Fatalf("FallbackTeams expected [second first], got: %v", team.FallbackTeams)
+		}
+
+		team, err = teamService.SetFallbackTeams(ctx, entity.SetFallbackTeamsDTO{
+			TeamName:      "team1",
+			FallbackTeams: []string{"first"},
+		})
+		if err != nil {
+			t.Fatalf("SetFallbackTeams should succeed, got: %v", err)
+		}
+		if !slices.Equal(team.FallbackTeams, []string{"first"}) {
+			t.Fatalf("FallbackTeams expected [first], got: %v", team.FallbackTeams)
+		}
+	})
+}
+
+func TestCodeOwners(t *testing.T) {
+	t.Run("Invalid pattern", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "team1",
+			Members:  []entity.UserDTO{{UserId: "u0", Username: "user0", IsActive: true}},
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		_, err = teamService.SetCodeOwners(ctx, entity.TeamCodeOwnersDTO{
+			TeamName: "team1",
+			Rules:    []entity.CodeOwnerRuleDTO{{Pattern: "", Users: []string{"u0"}}},
+		})
+		if !errors.Is(err, errs.ErrInvalidCodeOwnerPattern) {
+			t.Fatalf("SetCodeOwners expected ErrInvalidCodeOwnerPattern, got: %v", err)
+		}
+	})
+	t.Run("Owners assigned first", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "dba",
+			Members:  []entity.UserDTO{{UserId: "d0", Username: "dba0", IsActive: true}},
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		users := make([]entity.UserDTO, 10)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		_, err = teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "team1",
+			Members:  users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		_, err = teamService.SetCodeOwners(ctx, entity.TeamCodeOwnersDTO{
+			TeamName: "team1",
+			Rules: []entity.CodeOwnerRuleDTO{
+				{Pattern: "*.go", Users: []string{"u9"}},
+				{Pattern: "/migrations/", Teams: []string{"dba"}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SetCodeOwners should succeed, got: %v", err)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+			ChangedPaths:    []string{"internal/service/pr.go", "migrations/000001_init_schema.up.sql"},
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		if len(res.PullRequest.AssignedReviewers) != 2 {
+			t.Fatalf("AssignedReviewers expected 2, got: %d", len(res.PullRequest.AssignedReviewers))
+		}
+		if !slices.Contains(res.PullRequest.AssignedReviewers, "u9") ||
+			!slices.Contains(res.PullRequest.AssignedReviewers, "d0") {
+			t.Fatalf("AssignedReviewers expected to contain owners u9 and d0, got: %v", res.PullRequest.AssignedReviewers)
+		}
+		if len(res.FallbackReviewers) != 0 {
+			t.Fatalf("FallbackReviewers expected to be empty, got: %v", res.FallbackReviewers)
+		}
+	})
+	t.Run("Owners capped at max reviewers", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 4)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		maxReviewers := 1
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:     "team1",
+			MaxReviewers: &maxReviewers,
+			Members:      users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		_, err = teamService.SetCodeOwners(ctx, entity.TeamCodeOwnersDTO{
+			TeamName: "team1",
+			Rules: []entity.CodeOwnerRuleDTO{
+				{Pattern: "*.go", Users: []string{"u2"}},
+				{Pattern: "/migrations/", Users: []string{"u3"}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SetCodeOwners should succeed, got: %v", err)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+			ChangedPaths:    []string{"internal/service/pr.go", "migrations/000001_init_schema.up.sql"},
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		if !slices.Equal(res.PullRequest.AssignedReviewers, []string{"u2"}) {
+			t.Fatalf("AssignedReviewers expected [u2], got: %v", res.PullRequest.AssignedReviewers)
+		}
+	})
+	t.Run("Owners keep the round robin cursor", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "dba",
+			Members: []entity.UserDTO{
+				{UserId: "d0", Username: "dba0", IsActive: true},
+				{UserId: "d1", Username: "dba1", IsActive: true},
+			},
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+		users := make([]entity.UserDTO, 4)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		maxReviewers := 1
+		_, err = teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:         "team1",
+			ReviewerStrategy: entity.StrategyRoundRobin,
+			MaxReviewers:     &maxReviewers,
+			Members:          users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+		_, err = teamService.SetCodeOwners(ctx, entity.TeamCodeOwnersDTO{
+			TeamName: "team1",
+			Rules:    []entity.CodeOwnerRuleDTO{{Pattern: "/migrations/", Teams: []string{"dba"}}},
+		})
+		if err != nil {
+			t.Fatalf("SetCodeOwners should succeed, got: %v", err)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+			ChangedPaths:    []string{"migrations/000001_init_schema.up.sql"},
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		if len(res.PullRequest.AssignedReviewers) != 1 ||
+			!strings.HasPrefix(res.PullRequest.AssignedReviewers[0], "d") {
+			t.Fatalf("AssignedReviewers expected one dba owner, got: %v", res.PullRequest.AssignedReviewers)
+		}
+
+		var cursor string
+		err = pool.QueryRow(ctx, "SELECT round_robin_cursor FROM teams WHERE name = $1", "team1").
+			Scan(&cursor)
+		if err != nil {
+			t.Fatalf("failed to read round robin cursor: %v", err)
+		}
+		if cursor != "" {
+			t.Fatalf("Round robin cursor expected to stay empty, got: %q", cursor)
+		}
+	})
+}
+
+func TestReviewerRules(t *testing.T) {
+	t.Run("Self rule", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		_, err := ruleService.SetRule(ctx, entity.ReviewerRuleDTO{
+			AuthorId:   "u0",
+			ReviewerId: "u0",
+			Kind:       entity.RuleExclude,
+		})
+		if !errors.Is(err, errs.ErrInvalidReviewerRule) {
+			t.Fatalf("SetRule expected ErrInvalidReviewerRule, got: %v", err)
+		}
+	})
+	t.Run("Exclusions and preferences", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 6)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "team1",
+			Members:  users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		for _, rule := range []entity.ReviewerRuleDTO{
+			{AuthorId: "u0", ReviewerId: "u1", Kind: entity.RuleExclude},
+			{AuthorId: "u0", ReviewerId: "u2", Kind: entity.RuleExclude},
+			{AuthorId: "u0", ReviewerId: "u5", Kind: entity.RulePrefer},
+		} {
+			_, err = ruleService.SetRule(ctx, rule)
+			if err != nil {
+				t.Fatalf("SetRule should succeed, got: %v", err)
+			}
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		if slices.Contains(res.PullRequest.AssignedReviewers, "u1") ||
+			slices.Contains(res.PullRequest.AssignedReviewers, "u2") {
+			t.Fatalf("AssignedReviewers expected not to contain excluded users, got: %v", res.PullRequest.AssignedReviewers)
+		}
+		if !slices.Contains(res.PullRequest.AssignedReviewers, "u5") {
+			t.Fatalf("AssignedReviewers expected to contain preferred u5, got: %v", res.PullRequest.AssignedReviewers)
+		}
+
+		other := "u3"
+		if !slices.Contains(res.PullRequest.AssignedReviewers, other) {
+			other = "u4"
+		}
+		res, err = prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+			PullRequestId: "pr1",
+			OldReviewerId: "u5",
+		})
+		if err != nil {
+			t.Fatalf("ReassignPullRequest should succeed, got: %v", err)
+		}
+		if *res.ReplacedBy == "u1" || *res.ReplacedBy == "u2" || *res.ReplacedBy == other {
+			t.Fatalf("ReplacedBy expected to be a not excluded free user, got: %v", *res.ReplacedBy)
+		}
+	})
+}
+
+func TestAssignmentReplay(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 6)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:         "team1",
+		ReviewerStrategy: entity.StrategyWeighted,
+		Members:          users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	created, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if created.AssignmentId == nil {
+		t.Fatal("CreatePullRequest expected to return assignment_id")
+	}
+
+	reassigned, err := prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+		PullRequestId: "pr1",
+		OldReviewerId: created.PullRequest.AssignedReviewers[0],
+	})
+	if err != nil {
+		t.Fatalf("ReassignPullRequest should succeed, got: %v", err)
+	}
+
+	res, err := prService.GetAssignments(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("GetAssignments should succeed, got: %v", err)
+	}
+	if len(res.Assignments) != 2 {
+		t.Fatalf("Assignments expected 2, got: %d", len(res.Assignments))
+	}
+	if res.Assignments[0].Kind != entity.AssignmentCreate ||
+		res.Assignments[1].Kind != entity.AssignmentReassign {
+		t.Fatalf("Assignments expected CREATE and REASSIGN, got: %v", res.Assignments)
+	}
+	if !slices.Equal(res.Assignments[0].Reviewers, created.PullRequest.AssignedReviewers) {
+		t.Fatalf(
+			"Reviewers expected %v, got: %v",
+			created.PullRequest.AssignedReviewers,
+			res.Assignments[0].Reviewers,
+		)
+	}
+
+	for _, a := range res.Assignments {
+		replay, err := prService.ReplayAssignment(ctx, "pr1", a.AssignmentId)
+		if err != nil {
+			t.Fatalf("ReplayAssignment should succeed, got: %v", err)
+		}
+		if !replay.Matches || !slices.Equal(replay.ReplayedReviewers, a.Reviewers) {
+			t.Fatalf("ReplayAssignment expected %v, got: %v", a.Reviewers, replay.ReplayedReviewers)
+		}
+	}
+	if !slices.Equal(res.Assignments[1].Reviewers, []string{*reassigned.ReplacedBy}) {
+		t.Fatalf(
+			"Reviewers expected [%s], got: %v",
+			*reassigned.ReplacedBy,
+			res.Assignments[1].Reviewers,
+		)
+	}
+
+	_, err = prService.ReplayAssignment(ctx, "pr2", res.Assignments[0].AssignmentId)
+	if !errors.Is(err, errs.ErrBaseNotFound) {
+		t.Fatalf("ReplayAssignment for other PR should fail with ErrBaseNotFound, got: %v", err)
+	}
+}
+
+func TestSkillMatching(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 6)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	users[4].Skills = []string{"SQL", "go"}
+	maxReviewers := 1
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:     "team1",
+		MaxReviewers: &maxReviewers,
+		Members:      users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	team, err := teamService.GetTeam(ctx, "team1")
+	if err != nil {
+		t.Fatalf("GetTeam should succeed, got: %v", err)
+	}
+	for _, member := range team.Members {
+		if member.UserId == "u4" && !slices.Equal(member.Skills, []string{"go", "sql"}) {
+			t.Fatalf("Skills of u4 expected [go sql], got: %v", member.Skills)
+		}
+	}
+	limited, err := userService.SetMaxOpenReviews(ctx, entity.SetUserMaxOpenReviewsDTO{UserId: "u4"})
+	if err != nil {
+		t.Fatalf("SetMaxOpenReviews should succeed, got: %v", err)
+	}
+	if !slices.Equal(limited.Skills, []string{"go", "sql"}) {
+		t.Fatalf("SetMaxOpenReviews Skills of u4 expected [go sql], got: %v", limited.Skills)
+	}
+	activated, err := userService.SetIsActive(ctx, entity.SetUserIsActiveDTO{
+		UserId:   "u4",
+		IsActive: true,
+	})
+	if err != nil {
+		t.Fatalf("SetIsActive should succeed, got: %v", err)
+	}
+	if !slices.Equal(activated.Skills, []string{"go", "sql"}) {
+		t.Fatalf("SetIsActive Skills of u4 expected [go sql], got: %v", activated.Skills)
+	}
+
+	for i := range 3 {
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   fmt.Sprintf("pr%d", i),
+			PullRequestName: fmt.Sprintf("pr%d", i),
+			AuthorId:        "u0",
+			Labels:          []string{"sql"},
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		if !slices.Equal(res.PullRequest.AssignedReviewers, []string{"u4"}) {
+			t.Fatalf("AssignedReviewers expected [u4], got: %v", res.PullRequest.AssignedReviewers)
+		}
+	}
+
+	res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr3",
+		PullRequestName: "pr3",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if slices.Contains(res.PullRequest.AssignedReviewers, "u4") {
+		t.Fatalf("AssignedReviewers expected not to contain u4, got: %v", res.PullRequest.AssignedReviewers)
+	}
+
+	_, err = userService.SetSkills(ctx, entity.SetUserSkillsDTO{
+		UserId: "u5",
+		Skills: []string{"sql"},
+	})
+	if err != nil {
+		t.Fatalf("SetSkills should succeed, got: %v", err)
+	}
+	reassigned, err := prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+		PullRequestId: "pr0",
+		OldReviewerId: "u4",
+	})
+	if err != nil {
+		t.Fatalf("ReassignPullRequest should succeed, got: %v", err)
+	}
+	if *reassigned.ReplacedBy != "u5" {
+		t.Fatalf("ReplacedBy expected u5, got: %s", *reassigned.ReplacedBy)
+	}
+
+	_, err = userService.SetSkills(ctx, entity.SetUserSkillsDTO{
+		UserId: "u5",
+		Skills: []string{""},
+	})
+	if !errors.Is(err, errs.ErrInvalidTag) {
+		t.Fatalf("SetSkills with empty skill should fail with ErrInvalidTag, got: %v", err)
+	}
+
+	_, err = teamService.SetCodeOwners(ctx, entity.TeamCodeOwnersDTO{
+		TeamName: "team1",
+		Rules:    []entity.CodeOwnerRuleDTO{{Pattern: "*.go", Users: []string{"u1"}}},
+	})
+	if err != nil {
+		t.Fatalf("SetCodeOwners should succeed, got: %v", err)
+	}
+	res, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr4",
+		PullRequestName: "pr4",
+		AuthorId:        "u0",
+		ChangedPaths:    []string{"internal/service/pr.go"},
+		Labels:          []string{"sql"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if !slices.Equal(res.PullRequest.AssignedReviewers, []string{"u1"}) {
+		t.Fatalf("AssignedReviewers expected [u1], got: %v", res.PullRequest.AssignedReviewers)
+	}
+}
+
+func TestAbsences(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 4)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	from := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	to := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	vacation, err := userService.AddAbsence(ctx, entity.AbsenceDTO{
+		UserId: "u1",
+		Kind:   entity.AbsenceVacation,
+		From:   &from,
+		To:     &to,
+		Reason: "vacation",
+	})
+	if err != nil {
+		t.Fatalf("AddAbsence should succeed, got: %v", err)
+	}
+
+	weekday := int(time.Now().UTC().Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	_, err = userService.AddAbsence(ctx, entity.AbsenceDTO{
+		UserId:  "u2",
+		Kind:    entity.AbsenceDayOff,
+		Weekday: &weekday,
+	})
+	if err != nil {
+		t.Fatalf("AddAbsence should succeed, got: %v", err)
+	}
+
+	res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if !slices.Equal(res.PullRequest.AssignedReviewers, []string{"u3"}) {
+		t.Fatalf("AssignedReviewers expected [u3], got: %v", res.PullRequest.AssignedReviewers)
+	}
+
+	absences, err := userService.CancelAbsence(ctx, entity.CancelAbsenceDTO{
+		UserId:    "u1",
+		AbsenceId: vacation.AbsenceId,
+	})
+	if err != nil {
+		t.Fatalf("CancelAbsence should succeed, got: %v", err)
+	}
+	if len(absences.Absences) != 0 {
+		t.Fatalf("Absences of u1 expected to be empty, got: %v", absences.Absences)
+	}
+
+	res, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr2",
+		PullRequestName: "pr2",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if !slices.Contains(res.PullRequest.AssignedReviewers, "u1") ||
+		slices.Contains(res.PullRequest.AssignedReviewers, "u2") {
+		t.Fatalf("AssignedReviewers expected u1 and not u2, got: %v", res.PullRequest.AssignedReviewers)
+	}
+
+	_, err = userService.AddAbsence(ctx, entity.AbsenceDTO{
+		UserId: "u1",
+		Kind:   entity.AbsenceVacation,
+		From:   &to,
+		To:     &from,
+	})
+	if !errors.Is(err, errs.ErrInvalidAbsence) {
+		t.Fatalf("AddAbsence with reversed period should fail with ErrInvalidAbsence, got: %v", err)
+	}
+}
+
+func TestDeactivateReassignsReviews(t *testing.T) {
+	t.Run("Covered", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 5)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "team1",
+			Members:  users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		old := res.PullRequest.AssignedReviewers[0]
+
+		deactivated, err := userService.SetIsActive(ctx, entity.SetUserIsActiveDTO{
+			UserId:   old,
+			IsActive: false,
+		})
+		if err != nil {
+			t.Fatalf("SetIsActive should succeed, got: %v", err)
+		}
+		if len(deactivated.AffectedPullRequests) != 1 {
+			t.Fatalf("AffectedPullRequests expected 1, got: %v", deactivated.AffectedPullRequests)
+		}
+		affected := deactivated.AffectedPullRequests[0]
+		if affected.PullRequestId != "pr1" || affected.Uncovered || len(affected.ReplacedBy) != 1 {
+			t.Fatalf("AffectedPullRequests expected pr1 to be covered, got: %v", affected)
+		}
+		if slices.Contains(res.PullRequest.AssignedReviewers, affected.ReplacedBy[0]) {
+			t.Fatalf("ReplacedBy expected to be a new reviewer, got: %v", affected.ReplacedBy)
+		}
+
+		review, err := userService.GetReview(ctx, old)
+		if err != nil {
+			t.Fatalf("GetReview should succeed, got: %v", err)
+		}
+		if len(review.PullRequests) != 0 {
+			t.Fatalf("PullRequests of %s expected to be empty, got: %v", old, review.PullRequests)
+		}
+	})
+	t.Run("Uncovered", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 2)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "team1",
+			Members:  users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+
+		deactivated, err := userService.SetIsActive(ctx, entity.SetUserIsActiveDTO{
+			UserId:   "u1",
+			IsActive: false,
+		})
+		if err != nil {
+			t.Fatalf("SetIsActive should succeed, got: %v", err)
+		}
+		if len(deactivated.AffectedPullRequests) != 1 ||
+			!deactivated.AffectedPullRequests[0].Uncovered {
+			t.Fatalf(
+				"AffectedPullRequests expected pr1 to be uncovered, got: %v",
+				deactivated.AffectedPullRequests,
+			)
+		}
+	})
+	t.Run("Closed", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 5)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName: "team1",
+			Members:  users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		old := res.PullRequest.AssignedReviewers[0]
+		_, err = prService.ClosePullRequest(ctx, entity.ClosePullRequestDTO{PullRequestId: "pr1"})
+		if err != nil {
+			t.Fatalf("ClosePullRequest should succeed, got: %v", err)
+		}
+
+		deactivated, err := userService.SetIsActive(ctx, entity.SetUserIsActiveDTO{
+			UserId:   old,
+			IsActive: false,
+		})
+		if err != nil {
+			t.Fatalf("SetIsActive should succeed, got: %v", err)
+		}
+		if len(deactivated.AffectedPullRequests) != 1 ||
+			deactivated.AffectedPullRequests[0].Uncovered ||
+			len(deactivated.AffectedPullRequests[0].ReplacedBy) != 1 {
+			t.Fatalf(
+				"AffectedPullRequests expected pr1 to be covered, got: %v",
+				deactivated.AffectedPullRequests,
+			)
+		}
+
+		reopened, err := prService.ReopenPullRequest(ctx, entity.ReopenPullRequestDTO{PullRequestId: "pr1"})
+		if err != nil {
+			t.Fatalf("ReopenPullRequest should succeed, got: %v", err)
+		}
+		if slices.Contains(reopened.PullRequest.AssignedReviewers, old) {
+			t.Fatalf(
+				"AssignedReviewers expected not to contain %s, got: %v",
+				old,
+				reopened.PullRequest.AssignedReviewers,
+			)
+		}
+	})
+	t.Run("Below minimum", func(t *testing.T) {
+		ctx := setupTest(t)
+
+		users := make([]entity.UserDTO, 3)
+		for i := range users {
+			users[i] = entity.UserDTO{
+				UserId:   fmt.Sprintf("u%d", i),
+				Username: fmt.Sprintf("user%d", i),
+				IsActive: true,
+			}
+		}
+		minReviewers, maxReviewers := 1, 1
+		_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+			TeamName:     "team1",
+			MinReviewers: &minReviewers,
+			MaxReviewers: &maxReviewers,
+			Members:      users,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		old := res.PullRequest.AssignedReviewers[0]
+
+		minReviewers, maxReviewers = 2, 2
+		_, err = teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+			TeamName:     "team1",
+			MinReviewers: &minReviewers,
+			MaxReviewers: &maxReviewers,
+		})
+		if err != nil {
+			t.Fatalf("SetTeamSettings should succeed, got: %v", err)
+		}
+
+		deactivated, err := userService.SetIsActive(ctx, entity.SetUserIsActiveDTO{
+			UserId:   old,
+			IsActive: false,
+		})
+		if err != nil {
+			t.Fatalf("SetIsActive should succeed, got: %v", err)
+		}
+		if len(deactivated.AffectedPullRequests) != 1 {
+			t.Fatalf("AffectedPullRequests expected 1, got: %v", deactivated.AffectedPullRequests)
+		}
+		affected := deactivated.AffectedPullRequests[0]
+		if !affected.Uncovered || len(affected.ReplacedBy) != 1 {
+			t.Fatalf("AffectedPullRequests expected a partial replacement, got: %v", affected)
+		}
+	})
+}
+
+func TestReviewCapacity(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 3)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	minReviewers, teamLimit := 1, 1
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:       "team1",
+		MinReviewers:   &minReviewers,
+		MaxOpenReviews: &teamLimit,
+		Members:        users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	invalid := -1
+	_, err = userService.SetMaxOpenReviews(ctx, entity.SetUserMaxOpenReviewsDTO{
+		UserId:         "u2",
+		MaxOpenReviews: &invalid,
+	})
+	if !errors.Is(err, errs.ErrInvalidMaxOpenReviews) {
+		t.Fatalf("SetMaxOpenReviews with negative limit should fail, got: %v", err)
+	}
+	userLimit := 2
+	_, err = userService.SetMaxOpenReviews(ctx, entity.SetUserMaxOpenReviewsDTO{
+		UserId:         "u2",
+		MaxOpenReviews: &userLimit,
+	})
+	if err != nil {
+		t.Fatalf("SetMaxOpenReviews should succeed, got: %v", err)
+	}
+
+	res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if len(res.PullRequest.AssignedReviewers) != 2 {
+		t.Fatalf("AssignedReviewers expected to have len 2, got: %v", res.PullRequest.AssignedReviewers)
+	}
+
+	res, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr2",
+		PullRequestName: "pr2",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if !slices.Equal(res.PullRequest.AssignedReviewers, []string{"u2"}) {
+		t.Fatalf("AssignedReviewers expected [u2], got: %v", res.PullRequest.AssignedReviewers)
+	}
+
+	_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr3",
+		PullRequestName: "pr3",
+		AuthorId:        "u0",
+	})
+	if !errors.Is(err, errs.ErrReviewersAtCapacity) {
+		t.Fatalf("CreatePullRequest should fail with ErrReviewersAtCapacity, got: %v", err)
+	}
+
+	_, err = prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+		PullRequestId: "pr2",
+		OldReviewerId: "u2",
+	})
+	if !errors.Is(err, errs.ErrReviewersAtCapacity) {
+		t.Fatalf("ReassignPullRequest should fail with ErrReviewersAtCapacity, got: %v", err)
+	}
+
+	userLimit = 5
+	_, err = userService.SetMaxOpenReviews(ctx, entity.SetUserMaxOpenReviewsDTO{
+		UserId:         "u1",
+		MaxOpenReviews: &userLimit,
+	})
+	if err != nil {
+		t.Fatalf("SetMaxOpenReviews should succeed, got: %v", err)
+	}
+	res, err = prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+		PullRequestId: "pr2",
+		OldReviewerId: "u2",
+	})
+	if err != nil {
+		t.Fatalf("ReassignPullRequest should succeed, got: %v", err)
+	}
+	if res.ReplacedBy == nil || *res.ReplacedBy != "u1" {
+		t.Fatalf("ReplacedBy expected u1, got: %v", res.ReplacedBy)
+	}
+}
+
+func TestManualReviewers(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 8)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: i != 4,
+		}
+	}
+	noReviews := 0
+	users[6].MaxOpenReviews = &noReviews
+	minReviewers := 1
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:     "team1",
+		MinReviewers: &minReviewers,
+		Members:      users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+	from := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	to := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	_, err = userService.AddAbsence(ctx, entity.AbsenceDTO{
+		UserId: "u5",
+		Kind:   entity.AbsenceVacation,
+		From:   &from,
+		To:     &to,
+		Reason: "vacation",
+	})
+	if err != nil {
+		t.Fatalf("AddAbsence should succeed, got: %v", err)
+	}
+	_, err = ruleService.SetRule(ctx, entity.ReviewerRuleDTO{
+		AuthorId:   "u0",
+		ReviewerId: "u7",
+		Kind:       entity.RuleExclude,
+	})
+	if err != nil {
+		t.Fatalf("SetRule should succeed, got: %v", err)
+	}
+
+	res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	assigned := res.PullRequest.AssignedReviewers
+	if len(assigned) != 2 {
+		t.Fatalf("AssignedReviewers expected to have len 2, got: %v", assigned)
+	}
+	var free string
+	for _, id := range []string{"u1", "u2", "u3"} {
+		if !slices.Contains(assigned, id) {
+			free = id
+		}
+	}
+
+	for newId, expected := range map[string]error{
+		"u0":        errs.ErrReviewerIsAuthor,
+		"u4":        errs.ErrReviewerInactive,
+		assigned[1]: errs.ErrReviewerAlreadyAssigned,
+		"u5":        errs.ErrReviewerAbsent,
+		"u6":        errs.ErrReviewerAtCapacity,
+		"u7":        errs.ErrReviewerExcluded,
+		"u25":       errs.ErrBaseNotFound,
+	} {
+		_, err = prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+			PullRequestId: "pr1",
+			OldReviewerId: assigned[0],
+			NewReviewerId: &newId,
+		})
+		if !errors.Is(err, expected) {
+			t.Fatalf("ReassignPullRequest to %s should fail with %v, got: %v", newId, expected, err)
+		}
+	}
+
+	res, err = prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+		PullRequestId: "pr1",
+		OldReviewerId: assigned[0],
+		NewReviewerId: &free,
+	})
+	if err != nil {
+		t.Fatalf("ReassignPullRequest should succeed, got: %v", err)
+	}
+	if res.ReplacedBy == nil || *res.ReplacedBy != free {
+		t.Fatalf("ReplacedBy expected %s, got: %v", free, res.ReplacedBy)
+	}
+	if !slices.Contains(res.PullRequest.AssignedReviewers, free) ||
+		slices.Contains(res.PullRequest.AssignedReviewers, assigned[0]) {
+		t.Fatalf("AssignedReviewers expected %s instead of %s, got: %v", free, assigned[0], res.PullRequest.AssignedReviewers)
+	}
+	if res.AssignmentId == nil {
+		t.Fatalf("AssignmentId expected to be set")
+	}
+	if res.Assignment == nil || len(res.Assignment.Reviewers) != 1 ||
+		res.Assignment.Reviewers[0].UserId != free ||
+		res.Assignment.Reviewers[0].Reason != entity.ReasonManual {
+		t.Fatalf("Assignment expected to explain %s as manual, got: %+v", free, res.Assignment)
+	}
+	replay, err := prService.ReplayAssignment(ctx, "pr1", *res.AssignmentId)
+	if err != nil {
+		t.Fatalf("ReplayAssignment should succeed, got: %v", err)
+	}
+	if !replay.Matches || !slices.Equal(replay.Reviewers, []string{free}) {
+		t.Fatalf("ReplayAssignment expected to match [%s], got: %+v", free, replay)
+	}
+
+	for userId, expected := range map[string]error{
+		"u5": errs.ErrReviewerAbsent,
+		"u6": errs.ErrReviewerAtCapacity,
+		"u7": errs.ErrReviewerExcluded,
+	} {
+		_, err = prService.AddReviewer(ctx, entity.PullRequestReviewerDTO{
+			PullRequestId: "pr1",
+			UserId:        userId,
+		})
+		if !errors.Is(err, expected) {
+			t.Fatalf("AddReviewer of %s should fail with %v, got: %v", userId, expected, err)
+		}
+	}
+
+	res, err = prService.AddReviewer(ctx, entity.PullRequestReviewerDTO{
+		PullRequestId: "pr1",
+		UserId:        assigned[0],
+	})
+	if err != nil {
+		t.Fatalf("AddReviewer should succeed, got: %v", err)
+	}
+	if len(res.PullRequest.AssignedReviewers) != 3 {
+		t.Fatalf("AssignedReviewers expected to have len 3, got: %v", res.PullRequest.AssignedReviewers)
+	}
+	_, err = prService.AddReviewer(ctx, entity.PullRequestReviewerDTO{
+		PullRequestId: "pr1",
+		UserId:        assigned[0],
+	})
+	if !errors.Is(err, errs.ErrReviewerAlreadyAssigned) {
+		t.Fatalf("AddReviewer should fail with ErrReviewerAlreadyAssigned, got: %v", err)
+	}
+
+	_, err = prService.RemoveReviewer(ctx, entity.PullRequestReviewerDTO{
+		PullRequestId: "pr1",
+		UserId:        "u4",
+	})
+	if !errors.Is(err, errs.ErrUserNotAssigned) {
+		t.Fatalf("RemoveReviewer should fail with ErrUserNotAssigned, got: %v", err)
+	}
+	for _, id := range []string{assigned[0], assigned[1]} {
+		_, err = prService.RemoveReviewer(ctx, entity.PullRequestReviewerDTO{
+			PullRequestId: "pr1",
+			UserId:        id,
+		})
+		if err != nil {
+			t.Fatalf("RemoveReviewer should succeed, got: %v", err)
+		}
+	}
+	_, err = prService.RemoveReviewer(ctx, entity.PullRequestReviewerDTO{
+		PullRequestId: "pr1",
+		UserId:        free,
+	})
+	if !errors.Is(err, errs.ErrNotEnoughReviewers) {
+		t.Fatalf("RemoveReviewer should fail with ErrNotEnoughReviewers, got: %v", err)
+	}
+}
+
+func TestPreviewAssignment(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 5)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: i != 1,
+		}
+	}
+	noReviews := 0
+	users[2].MaxOpenReviews = &noReviews
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+	_, err = ruleService.SetRule(ctx, entity.ReviewerRuleDTO{
+		AuthorId:   "u0",
+		ReviewerId: "u3",
+		Kind:       entity.RuleExclude,
+	})
+	if err != nil {
+		t.Fatalf("SetRule should succeed, got: %v", err)
+	}
+
+	preview, err := prService.PreviewAssignment(ctx, entity.AssignmentPreviewRequestDTO{
+		AuthorId: "u0",
+	})
+	if err != nil {
+		t.Fatalf("PreviewAssignment should succeed, got: %v", err)
+	}
+	if !slices.Equal(preview.ProposedReviewers, []string{"u4"}) {
+		t.Fatalf("ProposedReviewers expected [u4], got: %v", preview.ProposedReviewers)
+	}
+	if len(preview.Eligible) != 1 || preview.Eligible[0].UserId != "u4" {
+		t.Fatalf("Eligible expected [u4], got: %v", preview.Eligible)
+	}
+	reasons := make(map[string]string)
+	for _, e := range preview.Excluded {
+		reasons[e.UserId] = e.Reason
+	}
+	expected := map[string]string{
+		"u0": entity.ExcludedAuthor,
+		"u1": entity.ExcludedInactive,
+		"u2": entity.ExcludedAtCapacity,
+		"u3": entity.ExcludedByRule,
+	}
+	if !maps.Equal(reasons, expected) {
+		t.Fatalf("Excluded expected %v, got: %v", expected, reasons)
+	}
+
+	stats, err := prService.GetOpenPullRequestsByReviewers(ctx)
+	if err != nil {
+		t.Fatalf("GetOpenPullRequestsByReviewers should succeed, got: %v", err)
+	}
+	if len(stats) != 0 {
+		t.Fatalf("PreviewAssignment should not assign reviewers, got: %v", stats)
+	}
+}
+
+func TestPreviewAssignmentSeed(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 4)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	seeded := service.NewPullRequestService(
+		logger,
+		pool,
+		postgres.NewPostgresPullRequestRepository(logger),
+		postgres.NewPostgresUserRepository(logger),
+		postgres.NewPostgresTeamRepository(logger),
+		postgres.NewPostgresReviewerRuleRepository(logger),
+		postgres.NewPostgresAssignmentRepository(logger),
+		postgres.NewPostgresReviewRepository(logger),
+		postgres.NewPostgresCommentRepository(logger),
+		rand.NewPCG(3, 4),
+	)
+	expected := rand.NewPCG(3, 4).Uint64()
+
+	_, err = seeded.PreviewAssignment(ctx, entity.AssignmentPreviewRequestDTO{AuthorId: "u0"})
+	if err != nil {
+		t.Fatalf("PreviewAssignment should succeed, got: %v", err)
+	}
+	_, err = seeded.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	res, err := seeded.GetAssignments(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("GetAssignments should succeed, got: %v", err)
+	}
+	if len(res.Assignments) != 1 || res.Assignments[0].Seed != expected {
+		t.Fatalf("Seed expected %d, got: %v", expected, res.Assignments)
+	}
+}
+
+func TestRebalanceTeam(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 4)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: i < 2,
+		}
+	}
+	maxReviewers := 1
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:     "team1",
+		MaxReviewers: &maxReviewers,
+		Members:      users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	for i := range 4 {
+		_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   fmt.Sprintf("pr%d", i),
+			PullRequestName: fmt.Sprintf("pr%d", i),
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+	}
+	for _, id := range []string{"u2", "u3"} {
+		_, err = userService.SetIsActive(ctx, entity.SetUserIsActiveDTO{UserId: id, IsActive: true})
+		if err != nil {
+			t.Fatalf("SetIsActive should succeed, got: %v", err)
+		}
+	}
+
+	res, err := prService.RebalanceTeam(ctx, entity.RebalanceTeamDTO{
+		TeamName: "team1",
+		DryRun:   true,
+	})
+	if err != nil {
+		t.Fatalf("RebalanceTeam should succeed, got: %v", err)
+	}
+	if len(res.Moves) != 2 {
+		t.Fatalf("Moves expected to have len 2, got: %v", res.Moves)
+	}
+	review, err := userService.GetReview(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetReview should succeed, got: %v", err)
+	}
+	if len(review.PullRequests) != 4 {
+		t.Fatalf("Dry run should not move reviews, got: %v", review.PullRequests)
+	}
+
+	res, err = prService.RebalanceTeam(ctx, entity.RebalanceTeamDTO{TeamName: "team1"})
+	if err != nil {
+		t.Fatalf("RebalanceTeam should succeed, got: %v", err)
+	}
+	if len(res.Moves) != 2 {
+		t.Fatalf("Moves expected to have len 2, got: %v", res.Moves)
+	}
+	for _, id := range []string{"u1", "u2", "u3"} {
+		review, err = userService.GetReview(ctx, id)
+		if err != nil {
+			t.Fatalf("GetReview should succeed, got: %v", err)
+		}
+		expected := 1
+		if id == "u1" {
+			expected = 2
+		}
+		if len(review.PullRequests) != expected {
+			t.Fatalf("%s expected to review %d PRs, got: %v", id, expected, review.PullRequests)
+		}
+	}
+
+	res, err = prService.RebalanceTeam(ctx, entity.RebalanceTeamDTO{TeamName: "team1"})
+	if err != nil {
+		t.Fatalf("RebalanceTeam should succeed, got: %v", err)
+	}
+	if len(res.Moves) != 0 {
+		t.Fatalf("Balanced team expected no moves, got: %v", res.Moves)
+	}
+}
+
+func TestAssignmentExplanation(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 4)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+	_, err = teamService.SetCodeOwners(ctx, entity.TeamCodeOwnersDTO{
+		TeamName: "team1",
+		Rules:    []entity.CodeOwnerRuleDTO{{Pattern: "docs/", Users: []string{"u2"}}},
+	})
+	if err != nil {
+		t.Fatalf("SetCodeOwners should succeed, got: %v", err)
+	}
+	_, err = ruleService.SetRule(ctx, entity.ReviewerRuleDTO{
+		AuthorId:   "u0",
+		ReviewerId: "u1",
+		Kind:       entity.RuleExclude,
+	})
+	if err != nil {
+		t.Fatalf("SetRule should succeed, got: %v", err)
+	}
+
+	res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+		ChangedPaths:    []string{"docs/readme.md"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	explanation := res.Assignment
+	if explanation == nil {
+		t.Fatal("Assignment explanation expected to be set")
+	}
+	if explanation.Strategy != entity.DefaultReviewerStrategy {
+		t.Fatalf("Strategy expected %s, got: %v", entity.DefaultReviewerStrategy, explanation.Strategy)
+	}
+	expected := []entity.ReviewerReasonDTO{
+		{UserId: "u2", TeamName: "team1", Reason: entity.ReasonOwnershipMatch},
+		{UserId: "u3", TeamName: "team1", Reason: entity.ReasonLeastLoaded},
+	}
+	if !slices.Equal(explanation.Reviewers, expected) {
+		t.Fatalf("Reviewers expected %v, got: %v", expected, explanation.Reviewers)
+	}
+	if explanation.PoolSize != 2 {
+		t.Fatalf("PoolSize expected 2, got: %v", explanation.PoolSize)
+	}
+	constraints := make(map[string][]string)
+	for _, c := range explanation.Constraints {
+		constraints[c.Reason] = c.UserIds
+	}
+	if !slices.Equal(constraints[entity.ExcludedAuthor], []string{"u0"}) ||
+		!slices.Equal(constraints[entity.ExcludedByRule], []string{"u1"}) {
+		t.Fatalf("Constraints expected author u0 and rule u1, got: %v", explanation.Constraints)
+	}
+}
+
+func TestRecentHistoryStrategy(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 3)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: i != 2,
+		}
+	}
+	maxReviewers, window := 1, 14
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:         "team1",
+		ReviewerStrategy: entity.StrategyRecentHistory,
+		MaxReviewers:     &maxReviewers,
+		ReviewWindowDays: &window,
+		Members:          users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	for i := range 3 {
+		prId := fmt.Sprintf("pr%d", i)
+		_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   prId,
+			PullRequestName: prId,
+			AuthorId:        "u0",
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: prId})
+		if err != nil {
+			t.Fatalf("MergePullRequest should succeed, got: %v", err)
+		}
+	}
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr0"})
+	if err != nil {
+		t.Fatalf("Repeated MergePullRequest should succeed, got: %v", err)
+	}
+	_, err = userService.SetIsActive(ctx, entity.SetUserIsActiveDTO{UserId: "u2", IsActive: true})
+	if err != nil {
+		t.Fatalf("SetIsActive should succeed, got: %v", err)
+	}
+
+	preview, err := prService.PreviewAssignment(ctx, entity.AssignmentPreviewRequestDTO{
+		AuthorId: "u0",
+	})
+	if err != nil {
+		t.Fatalf("PreviewAssignment should succeed, got: %v", err)
+	}
+	recent := make(map[string]int)
+	for _, c := range preview.Eligible {
+		recent[c.UserId] = c.RecentReviews
+	}
+	if recent["u1"] != 3 || recent["u2"] != 0 {
+		t.Fatalf("RecentReviews expected u1: 3 and u2: 0, got: %v", recent)
+	}
+
+	res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr3",
+		PullRequestName: "pr3",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if res.Assignment.Reviewers[0].Reason != entity.ReasonRecentHistory {
+		t.Fatalf("Reason expected %s, got: %v", entity.ReasonRecentHistory, res.Assignment.Reviewers)
+	}
+
+	window = 0
+	_, err = teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+		TeamName:         "team1",
+		ReviewWindowDays: &window,
+	})
+	if !errors.Is(err, errs.ErrInvalidReviewWindow) {
+		t.Fatalf("SetTeamSettings expected ErrInvalidReviewWindow, got: %v", err)
+	}
+	window = 7
+	team, err := teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+		TeamName:         "team1",
+		ReviewWindowDays: &window,
+	})
+	if err != nil {
+		t.Fatalf("SetTeamSettings should succeed, got: %v", err)
+	}
+	if *team.ReviewWindowDays != window {
+		t.Fatalf("ReviewWindowDays expected %d, got: %d", window, *team.ReviewWindowDays)
+	}
+}
+
+func TestCloseReopenPullRequest(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 4)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	created, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	assigned := created.PullRequest.AssignedReviewers
+
+	res, err := prService.ClosePullRequest(ctx, entity.ClosePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("ClosePullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.Status != entity.StatusClosed || res.PullRequest.ClosedAt == nil {
+		t.Fatalf("PullRequest expected to be closed, got: %+v", res.PullRequest)
+	}
+	prevClosedAt := *res.PullRequest.ClosedAt
+
+	res, err = prService.ClosePullRequest(ctx, entity.ClosePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("ClosePullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.ClosedAt == nil || *res.PullRequest.ClosedAt != prevClosedAt {
+		t.Fatalf("ClosedAt expected be %v, got: %v", prevClosedAt, res.PullRequest.ClosedAt)
+	}
+	_, err = pool.Exec(ctx, "UPDATE pull_requests SET updated_at = now() + interval '1 day' WHERE id = 'pr1'")
+	if err != nil {
+		t.Fatalf("updating updated_at should succeed, got: %v", err)
+	}
+	res, err = prService.GetPullRequest(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("GetPullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.ClosedAt == nil || *res.PullRequest.ClosedAt != prevClosedAt {
+		t.Fatalf("ClosedAt expected to stay %v after updates, got: %v", prevClosedAt, res.PullRequest.ClosedAt)
+	}
+
+	stats, err := prService.GetOpenPullRequestsByReviewers(ctx)
+	if err != nil {
+		t.Fatalf("GetOpenPullRequestsByReviewers should succeed, got: %v", err)
+	}
+	for _, s := range stats {
+		if s.OpenPullRequests != 0 {
+			t.Fatalf("closed PR should not be counted, got: %+v", s)
+		}
+	}
+
+	_, err = prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+		PullRequestId: "pr1",
+		OldReviewerId: assigned[0],
+	})
+	if !errors.Is(err, errs.ErrReassignOnClosedPR) {
+		t.Fatalf("ReassignPullRequest should fail with %v, got: %v", errs.ErrReassignOnClosedPR, err)
+	}
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if !errors.Is(err, errs.ErrMergeClosedPR) {
+		t.Fatalf("MergePullRequest should fail with %v, got: %v", errs.ErrMergeClosedPR, err)
+	}
+
+	res, err = prService.ReopenPullRequest(ctx, entity.ReopenPullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("ReopenPullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.Status != entity.StatusOpen || res.PullRequest.ClosedAt != nil {
+		t.Fatalf("PullRequest expected to be open, got: %+v", res.PullRequest)
+	}
+	reviewers := slices.Sorted(slices.Values(res.PullRequest.AssignedReviewers))
+	if !slices.Equal(reviewers, slices.Sorted(slices.Values(assigned))) {
+		t.Fatalf("AssignedReviewers expected %v, got: %v", assigned, res.PullRequest.AssignedReviewers)
+	}
+
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("MergePullRequest should succeed, got: %v", err)
+	}
+	_, err = prService.ClosePullRequest(ctx, entity.ClosePullRequestDTO{PullRequestId: "pr1"})
+	if !errors.Is(err, errs.ErrCloseMergedPR) {
+		t.Fatalf("ClosePullRequest should fail with %v, got: %v", errs.ErrCloseMergedPR, err)
+	}
+}
+
+func TestDraftPullRequest(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 4)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+		Draft:           true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.Status != entity.StatusDraft {
+		t.Fatalf("Status expected %s, got: %s", entity.StatusDraft, res.PullRequest.Status)
+	}
+	if len(res.PullRequest.AssignedReviewers) != 0 {
+		t.Fatalf("AssignedReviewers expected to be empty, got: %v", res.PullRequest.AssignedReviewers)
+	}
+
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if !errors.Is(err, errs.ErrDraftPR) {
+		t.Fatalf("MergePullRequest should fail with %v, got: %v", errs.ErrDraftPR, err)
+	}
+	_, err = prService.AddReviewer(ctx, entity.PullRequestReviewerDTO{
+		PullRequestId: "pr1",
+		UserId:        "u1",
+	})
+	if !errors.Is(err, errs.ErrDraftPR) {
+		t.Fatalf("AddReviewer should fail with %v, got: %v", errs.ErrDraftPR, err)
+	}
+	res, err = prService.ReopenPullRequest(ctx, entity.ReopenPullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("ReopenPullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.Status != entity.StatusDraft {
+		t.Fatalf("Status expected %s, got: %s", entity.StatusDraft, res.PullRequest.Status)
+	}
+	res, err = prService.ClosePullRequest(ctx, entity.ClosePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("ClosePullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.Status != entity.StatusClosed {
+		t.Fatalf("Status expected %s, got: %s", entity.StatusClosed, res.PullRequest.Status)
+	}
+	res, err = prService.ReopenPullRequest(ctx, entity.ReopenPullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("ReopenPullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.Status != entity.StatusDraft {
+		t.Fatalf("Status expected %s, got: %s", entity.StatusDraft, res.PullRequest.Status)
+	}
+
+	res, err = prService.MarkReadyPullRequest(ctx, entity.MarkReadyPullRequestDTO{
+		PullRequestId: "pr1",
+	})
+	if err != nil {
+		t.Fatalf("MarkReadyPullRequest should succeed, got: %v", err)
+	}
+	if res.PullRequest.Status != entity.StatusOpen {
+		t.Fatalf("Status expected %s, got: %s", entity.StatusOpen, res.PullRequest.Status)
+	}
+	assigned := res.PullRequest.AssignedReviewers
+	if len(assigned) != 2 || slices.Contains(assigned, "u0") {
+		t.Fatalf("AssignedReviewers expected 2 reviewers without author, got: %v", assigned)
+	}
+	if res.AssignmentId == nil {
+		t.Fatal("AssignmentId should not be nil")
+	}
+
+	res, err = prService.MarkReadyPullRequest(ctx, entity.MarkReadyPullRequestDTO{
+		PullRequestId: "pr1",
+	})
+	if err != nil {
+		t.Fatalf("MarkReadyPullRequest should succeed, got: %v", err)
+	}
+	reviewers := slices.Sorted(slices.Values(res.PullRequest.AssignedReviewers))
+	if !slices.Equal(reviewers, slices.Sorted(slices.Values(assigned))) {
+		t.Fatalf("AssignedReviewers expected %v, got: %v", assigned, res.PullRequest.AssignedReviewers)
+	}
+
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("MergePullRequest should succeed, got: %v", err)
+	}
+	_, err = prService.MarkReadyPullRequest(ctx, entity.MarkReadyPullRequestDTO{
+		PullRequestId: "pr1",
+	})
+	if !errors.Is(err, errs.ErrNotDraftPR) {
+		t.Fatalf("MarkReadyPullRequest should fail with %v, got: %v", errs.ErrNotDraftPR, err)
+	}
+}
+
+func TestReviews(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 4)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	requiredApprovals := 2
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:          "team1",
+		RequiredApprovals: &requiredApprovals,
+		Members:           users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	created, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	assigned := created.PullRequest.AssignedReviewers
+	var free string
+	for _, id := range []string{"u1", "u2", "u3"} {
+		if !slices.Contains(assigned, id) {
+			free = id
+		}
+	}
+
+	_, err = prService.SubmitReview(ctx, entity.SubmitReviewDTO{
+		PullRequestId: "pr1",
+		UserId:        free,
+		Decision:      entity.ReviewApproved,
+	})
+	if !errors.Is(err, errs.ErrUserNotAssigned) {
+		t.Fatalf("SubmitReview should fail with %v, got: %v", errs.ErrUserNotAssigned, err)
+	}
+	_, err = prService.SubmitReview(ctx, entity.SubmitReviewDTO{
+		PullRequestId: "pr1",
+		UserId:        assigned[0],
+		Decision:      "LGTM",
+	})
+	if !errors.Is(err, errs.ErrBaseBadRequest) {
+		t.Fatalf("SubmitReview should fail with %v, got: %v", errs.ErrBaseBadRequest, err)
+	}
+
+	res, err := prService.SubmitReview(ctx, entity.SubmitReviewDTO{
+		PullRequestId: "pr1",
+		UserId:        assigned[0],
+		Decision:      entity.ReviewApproved,
+	})
+	if err != nil {
+		t.Fatalf("SubmitReview should succeed, got: %v", err)
+	}
+	if len(res.PullRequest.Reviews) != 1 || res.PullRequest.Reviews[0].UserId != assigned[0] {
+		t.Fatalf("Reviews expected to have approval of %s, got: %v", assigned[0], res.PullRequest.Reviews)
+	}
+
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if !errors.Is(err, errs.ErrNotEnoughApprovals) {
+		t.Fatalf("MergePullRequest should fail with %v, got: %v", errs.ErrNotEnoughApprovals, err)
+	}
+
+	_, err = prService.SubmitReview(ctx, entity.SubmitReviewDTO{
+		PullRequestId: "pr1",
+		UserId:        assigned[1],
+		Decision:      entity.ReviewChangesRequested,
+		Comment:       "please add tests",
+	})
+	if err != nil {
+		t.Fatalf("SubmitReview should succeed, got: %v", err)
+	}
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if !errors.Is(err, errs.ErrChangesRequested) {
+		t.Fatalf("MergePullRequest should fail with %v, got: %v", errs.ErrChangesRequested, err)
+	}
+
+	_, err = prService.SubmitReview(ctx, entity.SubmitReviewDTO{
+		PullRequestId: "pr1",
+		UserId:        assigned[1],
+		Decision:      entity.ReviewApproved,
+	})
+	if err != nil {
+		t.Fatalf("SubmitReview should succeed, got: %v", err)
+	}
+	merged, err := prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("MergePullRequest should succeed, got: %v", err)
+	}
+	if len(merged.PullRequest.Reviews) != 2 {
+		t.Fatalf("Reviews expected to have len 2, got: %v", merged.PullRequest.Reviews)
+	}
+
+	_, err = prService.SubmitReview(ctx, entity.SubmitReviewDTO{
+		PullRequestId: "pr1",
+		UserId:        assigned[0],
+		Decision:      entity.ReviewApproved,
+	})
+	if !errors.Is(err, errs.ErrReviewOnMergedPR) {
+		t.Fatalf("SubmitReview should fail with %v, got: %v", errs.ErrReviewOnMergedPR, err)
+	}
+	requiredApprovals = 3
+	_, err = teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+		TeamName:          "team1",
+		RequiredApprovals: &requiredApprovals,
+	})
+	if !errors.Is(err, errs.ErrInvalidRequiredApprovals) {
+		t.Fatalf("SetTeamSettings should fail with %v, got: %v", errs.ErrInvalidRequiredApprovals, err)
+	}
+	requiredApprovals = 0
+	_, err = teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+		TeamName:          "team1",
+		RequiredApprovals: &requiredApprovals,
+	})
+	if err != nil {
+		t.Fatalf("SetTeamSettings should succeed, got: %v", err)
+	}
+	_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr2",
+		PullRequestName: "pr2",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr2"})
+	if err != nil {
+		t.Fatalf("MergePullRequest without required approvals should succeed, got: %v", err)
+	}
+}
+
+func TestPullRequestHistory(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 5)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	created, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	if created.PullRequest.CreatedAt == nil {
+		t.Fatal("CreatedAt should not be nil")
+	}
+	assigned := created.PullRequest.AssignedReviewers
+
+	_, err = prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+		PullRequestId: "pr1",
+		OldReviewerId: assigned[0],
+		ActorId:       "u25",
+	})
+	if !errors.Is(err, errs.ErrBaseNotFound) {
+		t.Fatalf("ReassignPullRequest should fail with %v, got: %v", errs.ErrBaseNotFound, err)
+	}
+	reassigned, err := prService.ReassignPullRequest(ctx, entity.ReassignPullRequestDTO{
+		PullRequestId: "pr1",
+		OldReviewerId: assigned[0],
+		ActorId:       "u4",
+	})
+	if err != nil {
+		t.Fatalf("ReassignPullRequest should succeed, got: %v", err)
+	}
+	_, err = prService.ClosePullRequest(ctx, entity.ClosePullRequestDTO{
+		PullRequestId: "pr1",
+		ActorId:       "u0",
+	})
+	if err != nil {
+		t.Fatalf("ClosePullRequest should succeed, got: %v", err)
+	}
+	_, err = prService.ReopenPullRequest(ctx, entity.ReopenPullRequestDTO{
+		PullRequestId: "pr1",
+		ActorId:       "u0",
+	})
+	if err != nil {
+		t.Fatalf("ReopenPullRequest should succeed, got: %v", err)
+	}
+	merged, err := prService.MergePullRequest(ctx, entity.MergePullRequestDTO{
+		PullRequestId: "pr1",
+		ActorId:       "u0",
+	})
+	if err != nil {
+		t.Fatalf("MergePullRequest should succeed, got: %v", err)
+	}
+
+	again, err := prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("MergePullRequest should succeed, got: %v", err)
+	}
+	if again.PullRequest.MergedAt == nil || *again.PullRequest.MergedAt != *merged.PullRequest.MergedAt {
+		t.Fatalf("MergedAt expected %v, got: %v", *merged.PullRequest.MergedAt, again.PullRequest.MergedAt)
+	}
+
+	history, err := prService.GetPullRequestHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("GetPullRequestHistory should succeed, got: %v", err)
+	}
+	type event struct {
+		kind   string
+		actor  string
+		user   string
+		status string
+	}
+	expected := []event{
+		{entity.EventStatusChanged, "u0", "", entity.StatusOpen},
+		{entity.EventReviewerAdded, "u0", assigned[0], ""},
+		{entity.EventReviewerAdded, "u0", assigned[1], ""},
+		{entity.EventReviewerRemoved, "u4", assigned[0], ""},
+		{entity.EventReviewerAdded, "u4", *reassigned.ReplacedBy, ""},
+		{entity.EventStatusChanged, "u0", "", entity.StatusClosed},
+		{entity.EventStatusChanged, "u0", "", entity.StatusOpen},
+		{entity.EventStatusChanged, "u0", "", entity.StatusMerged},
+	}
+	got := make([]event, len(history.Events))
+	for i, e := range history.Events {
+		got[i].kind = e.Kind
+		if e.ActorId != nil {
+			got[i].actor = *e.ActorId
+		}
+		if e.UserId != nil {
+			got[i].user = *e.UserId
+		}
+		if e.ToStatus != nil {
+			got[i].status = *e.ToStatus
+		}
+	}
+	if !slices.Equal(got, expected) {
+		t.Fatalf("Events expected %v, got: %v", expected, got)
+	}
+}
+
+func TestUpdatePullRequest(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 3)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+		Labels:          []string{"go"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+
+	_, err = prService.UpdatePullRequest(ctx, entity.UpdatePullRequestDTO{PullRequestId: "pr1"})
+	if !errors.Is(err, errs.ErrEmptyPullRequestUpdate) {
+		t.Fatalf(
+			"UpdatePullRequest should fail with %v, got: %v",
+			errs.ErrEmptyPullRequestUpdate,
+			err,
+		)
+	}
+	empty := " "
+	_, err = prService.UpdatePullRequest(ctx, entity.UpdatePullRequestDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: &empty,
+	})
+	if !errors.Is(err, errs.ErrInvalidPullRequestName) {
+		t.Fatalf(
+			"UpdatePullRequest should fail with %v, got: %v",
+			errs.ErrInvalidPullRequestName,
+			err,
+		)
+	}
+	long := strings.Repeat("a", 4097)
+	_, err = prService.UpdatePullRequest(ctx, entity.UpdatePullRequestDTO{
+		PullRequestId: "pr1",
+		Description:   &long,
+	})
+	if !errors.Is(err, errs.ErrInvalidDescription) {
+		t.Fatalf("UpdatePullRequest should fail with %v, got: %v", errs.ErrInvalidDescription, err)
+	}
+
+	name := "renamed"
+	description := "adds update endpoint"
+	labels := []string{"sql", "api"}
+	updated, err := prService.UpdatePullRequest(ctx, entity.UpdatePullRequestDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: &name,
+		Description:     &description,
+		Labels:          &labels,
+		ActorId:         "u1",
+	})
+	if err != nil {
+		t.Fatalf("UpdatePullRequest should succeed, got: %v", err)
+	}
+	if updated.PullRequest.PullRequestName != name ||
+		updated.PullRequest.Description != description {
+		t.Fatalf("UpdatePullRequest should update fields, got: %v", updated.PullRequest)
+	}
+	if !slices.Equal(updated.PullRequest.Labels, []string{"api", "sql"}) {
+		t.Fatalf("Labels expected [api sql], got: %v", updated.PullRequest.Labels)
+	}
+
+	_, err = prService.UpdatePullRequest(ctx, entity.UpdatePullRequestDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: &name,
+		Labels:          &[]string{"api", "sql"},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePullRequest should succeed, got: %v", err)
+	}
+
+	history, err := prService.GetPullRequestHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("GetPullRequestHistory should succeed, got: %v", err)
+	}
+	var updates []entity.PullRequestEventDTO
+	for _, e := range history.Events {
+		if e.Kind == entity.EventUpdated {
+			updates = append(updates, e)
+		}
+	}
+	expectedFields := []string{entity.FieldName, entity.FieldDescription, entity.FieldLabels}
+	if len(updates) != 1 || !slices.Equal(updates[0].Fields, expectedFields) ||
+		updates[0].ActorId == nil || *updates[0].ActorId != "u1" {
+		t.Fatalf("History should contain one UPDATED event by u1, got: %v", updates)
+	}
+
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("MergePullRequest should succeed, got: %v", err)
+	}
+	_, err = prService.UpdatePullRequest(ctx, entity.UpdatePullRequestDTO{
+		PullRequestId: "pr1",
+		Description:   &description,
+	})
+	if !errors.Is(err, errs.ErrUpdateMergedPR) {
+		t.Fatalf("UpdatePullRequest should fail with %v, got: %v", errs.ErrUpdateMergedPR, err)
+	}
+}
+
+func TestListPullRequests(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 3)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	var created []string
+	for i := range 5 {
+		res, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   fmt.Sprintf("pr%d", i),
+			PullRequestName: fmt.Sprintf("pr%d", i),
+			AuthorId:        "u0",
+			Labels:          []string{fmt.Sprintf("label%d", i%2)},
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		created = append(created, res.PullRequest.PullRequestId)
+	}
+	slices.Reverse(created)
+
+	got, err := prService.GetPullRequest(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("GetPullRequest should succeed, got: %v", err)
+	}
+	if got.PullRequest.PullRequestId != "pr1" ||
+		!slices.Equal(got.PullRequest.Labels, []string{"label1"}) {
+		t.Fatalf("GetPullRequest expected pr1 with label1, got: %v", got.PullRequest)
+	}
+	_, err = prService.GetPullRequest(ctx, "pr25")
+	if !errors.Is(err, errs.ErrBaseNotFound) {
+		t.Fatalf("GetPullRequest should fail with %v, got: %v", errs.ErrBaseNotFound, err)
+	}
+
+	var listed []string
+	cursor := ""
+	for {
+		page, err := prService.ListPullRequests(ctx, entity.ListPullRequestsDTO{
+			Limit:  2,
+			Cursor: cursor,
+		})
+		if err != nil {
+			t.Fatalf("ListPullRequests should succeed, got: %v", err)
+		}
+		if len(page.PullRequests) > 2 {
+			t.Fatalf("Page should have at most 2 PRs, got: %d", len(page.PullRequests))
+		}
+		for _, pr := range page.PullRequests {
+			listed = append(listed, pr.PullRequestId)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		cursor = *page.NextCursor
+	}
+	if !slices.Equal(listed, created) {
+		t.Fatalf("Pages expected %v, got: %v", created, listed)
+	}
+
+	page, err := prService.ListPullRequests(ctx, entity.ListPullRequestsDTO{
+		Label:  "label1",
+		SortBy: entity.SortName,
+	})
+	if err != nil {
+		t.Fatalf("ListPullRequests should succeed, got: %v", err)
+	}
+	if len(page.PullRequests) != 2 || page.PullRequests[0].PullRequestId != "pr1" ||
+		page.PullRequests[1].PullRequestId != "pr3" || page.NextCursor != nil {
+		t.Fatalf("ListPullRequests expected [pr1 pr3], got: %v", page.PullRequests)
+	}
+
+	reviewer := got.PullRequest.AssignedReviewers[0]
+	page, err = prService.ListPullRequests(ctx, entity.ListPullRequestsDTO{ReviewerId: reviewer})
+	if err != nil {
+		t.Fatalf("ListPullRequests should succeed, got: %v", err)
+	}
+	if !slices.ContainsFunc(page.PullRequests, func(pr entity.PullRequestDTO) bool {
+		return pr.PullRequestId == "pr1"
+	}) {
+		t.Fatalf("ListPullRequests by reviewer should contain pr1, got: %v", page.PullRequests)
+	}
+
+	first, err := prService.ListPullRequests(ctx, entity.ListPullRequestsDTO{Limit: 1})
+	if err != nil {
+		t.Fatalf("ListPullRequests should succeed, got: %v", err)
+	}
+	invalid := []entity.ListPullRequestsDTO{
+		{Status: "UNKNOWN"},
+		{SortBy: "author"},
+		{Order: "up"},
+		{Limit: 101},
+		{Cursor: "not a cursor"},
+		{Cursor: *first.NextCursor, SortBy: entity.SortName},
+	}
+	for _, dto := range invalid {
+		_, err = prService.ListPullRequests(ctx, dto)
+		if !errors.Is(err, errs.ErrBaseBadFilter) {
+			t.Fatalf(
+				"ListPullRequests(%v) should fail with %v, got: %v",
+				dto,
+				errs.ErrBaseBadFilter,
+				err,
+			)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 4)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	requireResolved := true
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName:                "team1",
+		RequireResolvedComments: &requireResolved,
+		Members:                 users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	created, err := prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	reviewer := created.PullRequest.AssignedReviewers[0]
+	outsider := ""
+	for _, u := range users[1:] {
+		if !slices.Contains(created.PullRequest.AssignedReviewers, u.UserId) {
+			outsider = u.UserId
+		}
+	}
+
+	_, err = prService.AddComment(ctx, entity.AddCommentDTO{
+		PullRequestId: "pr1",
+		UserId:        reviewer,
+		Body:          " ",
+	})
+	if !errors.Is(err, errs.ErrInvalidComment) {
+		t.Fatalf("AddComment should fail with %v, got: %v", errs.ErrInvalidComment, err)
+	}
+	_, err = prService.AddComment(ctx, entity.AddCommentDTO{
+		PullRequestId: "pr1",
+		UserId:        outsider,
+		Body:          "looks good",
+	})
+	if !errors.Is(err, errs.ErrNotParticipant) {
+		t.Fatalf("AddComment should fail with %v, got: %v", errs.ErrNotParticipant, err)
+	}
+
+	thread, err := prService.AddComment(ctx, entity.AddCommentDTO{
+		PullRequestId: "pr1",
+		UserId:        reviewer,
+		Body:          "why?",
+	})
+	if err != nil {
+		t.Fatalf("AddComment should succeed, got: %v", err)
+	}
+	threadId := thread.Comment.CommentId
+	reply, err := prService.AddComment(ctx, entity.AddCommentDTO{
+		PullRequestId: "pr1",
+		UserId:        "u0",
+		Body:          "because",
+		ParentId:      &threadId,
+	})
+	if err != nil {
+		t.Fatalf("AddComment should succeed, got: %v", err)
+	}
+	nested, err := prService.AddComment(ctx, entity.AddCommentDTO{
+		PullRequestId: "pr1",
+		UserId:        reviewer,
+		Body:          "ok",
+		ParentId:      &reply.Comment.CommentId,
+	})
+	if err != nil {
+		t.Fatalf("AddComment should succeed, got: %v", err)
+	}
+	if nested.Comment.ParentId == nil || *nested.Comment.ParentId != threadId {
+		t.Fatalf("Reply to a reply should belong to thread %d, got: %v", threadId, nested.Comment)
+	}
+
+	pr, err := prService.GetPullRequest(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("GetPullRequest should succeed, got: %v", err)
+	}
+	if pr.PullRequest.UnresolvedThreads != 1 {
+		t.Fatalf("UnresolvedThreads expected 1, got: %d", pr.PullRequest.UnresolvedThreads)
+	}
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if !errors.Is(err, errs.ErrUnresolvedComments) {
+		t.Fatalf("MergePullRequest should fail with %v, got: %v", errs.ErrUnresolvedComments, err)
+	}
+
+	_, err = prService.ResolveComment(ctx, entity.ResolveCommentDTO{
+		CommentId: threadId,
+		UserId:    outsider,
+	})
+	if !errors.Is(err, errs.ErrNotParticipant) {
+		t.Fatalf("ResolveComment should fail with %v, got: %v", errs.ErrNotParticipant, err)
+	}
+	resolved, err := prService.ResolveComment(ctx, entity.ResolveCommentDTO{
+		CommentId: nested.Comment.CommentId,
+		UserId:    reviewer,
+	})
+	if err != nil {
+		t.Fatalf("ResolveComment should succeed, got: %v", err)
+	}
+	if resolved.Comment.CommentId != threadId || !resolved.Comment.Resolved ||
+		len(resolved.Comment.Replies) != 2 {
+		t.Fatalf("ResolveComment should resolve the whole thread, got: %v", resolved.Comment)
+	}
+
+	comments, err := prService.GetComments(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("GetComments should succeed, got: %v", err)
+	}
+	if len(comments.Threads) != 1 || !comments.Threads[0].Resolved {
+		t.Fatalf("GetComments expected single resolved thread, got: %v", comments.Threads)
+	}
+
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr1"})
+	if err != nil {
+		t.Fatalf("MergePullRequest should succeed, got: %v", err)
+	}
+	_, err = prService.AddComment(ctx, entity.AddCommentDTO{
+		PullRequestId: "pr1",
+		UserId:        reviewer,
+		Body:          "late",
+	})
+	if !errors.Is(err, errs.ErrCommentOnMergedPR) {
+		t.Fatalf("AddComment should fail with %v, got: %v", errs.ErrCommentOnMergedPR, err)
+	}
+
+	created, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr2",
+		PullRequestName: "pr2",
+		AuthorId:        "u0",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+	}
+	_, err = prService.AddComment(ctx, entity.AddCommentDTO{
+		PullRequestId: "pr2",
+		UserId:        created.PullRequest.AssignedReviewers[0],
+		Body:          "why?",
+	})
+	if err != nil {
+		t.Fatalf("AddComment should succeed, got: %v", err)
+	}
+	requireResolved = false
+	team, err := teamService.SetTeamSettings(ctx, entity.TeamSettingsDTO{
+		TeamName:                "team1",
+		RequireResolvedComments: &requireResolved,
+	})
+	if err != nil {
+		t.Fatalf("SetTeamSettings should succeed, got: %v", err)
+	}
+	if *team.RequireResolvedComments {
+		t.Fatalf("RequireResolvedComments expected to be false")
+	}
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr2"})
+	if err != nil {
+		t.Fatalf("MergePullRequest with unresolved threads should succeed, got: %v", err)
+	}
+}
+
+func TestStackedPullRequests(t *testing.T) {
+	ctx := setupTest(t)
+
+	users := make([]entity.UserDTO, 3)
+	for i := range users {
+		users[i] = entity.UserDTO{
+			UserId:   fmt.Sprintf("u%d", i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	_, err := teamService.AddTeam(ctx, entity.TeamDTO{
+		TeamName: "team1",
+		Members:  users,
+	})
+	if err != nil {
+		t.Fatalf("AddTeam should succeed, got: %v", err)
+	}
+
+	var parentId *string
+	var top *entity.PullRequestResponseDTO
+	for _, id := range []string{"pr1", "pr2", "pr3"} {
+		top, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestId:   id,
+			PullRequestName: id,
+			AuthorId:        "u0",
+			ParentId:        parentId,
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+		parentId = &id
+	}
+	expected := []entity.PullRequestDependencyDTO{
+		{PullRequestId: "pr2", Status: entity.StatusOpen},
+		{PullRequestId: "pr1", Status: entity.StatusOpen},
+	}
+	if !slices.Equal(top.PullRequest.Dependencies, expected) {
+		t.Fatalf("Dependencies expected %v, got: %v", expected, top.PullRequest.Dependencies)
+	}
+
+	missing := "pr25"
+	_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "pr4",
+		PullRequestName: "pr4",
+		AuthorId:        "u0",
+		ParentId:        &missing,
+	})
+	if !errors.Is(err, errs.ErrBaseNotFound) {
+		t.Fatalf("CreatePullRequest should fail with %v, got: %v", errs.ErrBaseNotFound, err)
+	}
+	for _, parent := range []string{"pr1", "pr3"} {
+		_, err = prService.UpdatePullRequest(ctx, entity.UpdatePullRequestDTO{
+			PullRequestId: "pr1",
+			ParentId:      &parent,
+		})
+		if !errors.Is(err, errs.ErrDependencyCycle) {
+			t.Fatalf("UpdatePullRequest should fail with %v, got: %v", errs.ErrDependencyCycle, err)
+		}
+	}
+
+	_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: "pr2"})
+	if !errors.Is(err, errs.ErrParentNotMerged) {
+		t.Fatalf("MergePullRequest should fail with %v, got: %v", errs.ErrParentNotMerged, err)
+	}
+	for _, id := range []string{"pr1", "pr2"} {
+		_, err = prService.MergePullRequest(ctx, entity.MergePullRequestDTO{PullRequestId: id})
+		if err != nil {
+			t.Fatalf("MergePullRequest should succeed, got: %v", err)
+		}
+	}
+
+	unstack := ""
+	updated, err := prService.UpdatePullRequest(ctx, entity.UpdatePullRequestDTO{
+		PullRequestId: "pr3",
+		ParentId:      &unstack,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePullRequest should succeed, got: %v", err)
+	}
+	if updated.PullRequest.ParentId != nil || len(updated.PullRequest.Dependencies) != 0 {
+		t.Fatalf("UpdatePullRequest should unstack the PR, got: %v", updated.PullRequest)
+	}
+	history, err := prService.GetPullRequestHistory(ctx, "pr3")
+	if err != nil {
+		t.Fatalf("GetPullRequestHistory should succeed, got: %v", err)
+	}
+	last := history.Events[len(history.Events)-1]
+	if last.Kind != entity.EventUpdated ||
+		!slices.Equal(last.Fields, []string{entity.FieldParent}) {
+		t.Fatalf("History should end with parent update, got: %v", last)
+	}
+}
+
+func TestRepositories(t *testing.T) {
+	ctx := setupTest(t)
+
+	for _, team := range []entity.TeamDTO{
+		{
+			TeamName: "app",
+			Members: []entity.UserDTO{
+				{UserId: "a0", Username: "app0", IsActive: true},
+				{UserId: "a1", Username: "app1", IsActive: true},
+			},
+		},
+		{
+			TeamName: "platform",
+			Members: []entity.UserDTO{
+				{UserId: "p0", Username: "platform0", IsActive: true},
+				{UserId: "p1", Username: "platform1", IsActive: true},
+			},
+		},
+	} {
+		_, err := teamService.AddTeam(ctx, team)
+		if err != nil {
+			t.Fatalf("AddTeam should succeed, got: %v", err)
+		}
+	}
+
+	_, err := teamService.SetRepository(ctx, entity.RepositoryDTO{
+		RepositoryName: "web#1",
+		TeamName:       "app",
+	})
+	if !errors.Is(err, errs.ErrInvalidRepository) {
+		t.Fatalf("SetRepository should fail with %v, got: %v", errs.ErrInvalidRepository, err)
+	}
+	for _, repo := range []entity.RepositoryDTO{
+		{RepositoryName: "web", TeamName: "app"},
+		{RepositoryName: "infra", TeamName: "platform", RouteReviewsToOwner: true},
+	} {
+		_, err = teamService.SetRepository(ctx, repo)
+		if err != nil {
+			t.Fatalf("SetRepository should succeed, got: %v", err)
+		}
+	}
+	repos, err := teamService.GetRepositories(ctx, "platform")
+	if err != nil {
+		t.Fatalf("GetRepositories should succeed, got: %v", err)
+	}
+	if len(repos.Repositories) != 1 || repos.Repositories[0].RepositoryName != "infra" {
+		t.Fatalf("GetRepositories should return [infra], got: %v", repos.Repositories)
+	}
+
+	number := 42
+	created := make(map[string]*entity.PullRequestResponseDTO)
+	for _, name := range []string{"web", "infra"} {
+		created[name], err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+			PullRequestName: "change",
+			AuthorId:        "a0",
+			RepositoryName:  &name,
+			Number:          &number,
+		})
+		if err != nil {
+			t.Fatalf("CreatePullRequest should succeed, got: %v", err)
+		}
+	}
+	if created["web"].PullRequest.PullRequestId != "web#42" {
+		t.Fatalf("PR id should be web#42, got: %v", created["web"].PullRequest.PullRequestId)
+	}
+	if !slices.Equal(created["web"].PullRequest.AssignedReviewers, []string{"a1"}) {
+		t.Fatalf(
+			"PR of web should be reviewed by the author team, got: %v",
+			created["web"].PullRequest.AssignedReviewers,
+		)
+	}
+	reviewers := slices.Sorted(slices.Values(created["infra"].PullRequest.AssignedReviewers))
+	if !slices.Equal(reviewers, []string{"p0", "p1"}) {
+		t.Fatalf("PR of infra should be reviewed by the owning team, got: %v", reviewers)
+	}
+
+	web := "web"
+	_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestName: "change",
+		AuthorId:        "a0",
+		RepositoryName:  &web,
+		Number:          &number,
+	})
+	if !errors.Is(err, errs.ErrPullRequestAlreadyExists) {
+		t.Fatalf(
+			"CreatePullRequest should fail with %v, got: %v",
+			errs.ErrPullRequestAlreadyExists,
+			err,
+		)
+	}
+	other := 43
+	_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "other",
+		PullRequestName: "change",
+		AuthorId:        "a0",
+		RepositoryName:  &web,
+		Number:          &other,
+	})
+	if !errors.Is(err, errs.ErrRepositoryPullRequestId) {
+		t.Fatalf(
+			"CreatePullRequest should fail with %v, got: %v",
+			errs.ErrRepositoryPullRequestId,
+			err,
+		)
+	}
+	_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestName: "change",
+		AuthorId:        "a0",
+		Number:          &number,
+	})
+	if !errors.Is(err, errs.ErrInvalidPullRequestNumber) {
+		t.Fatalf(
+			"CreatePullRequest should fail with %v, got: %v",
+			errs.ErrInvalidPullRequestNumber,
+			err,
+		)
+	}
+	_, err = prService.CreatePullRequest(ctx, entity.PullRequestCreateDTO{
+		PullRequestId:   "web#43",
+		PullRequestName: "change",
+		AuthorId:        "a0",
+	})
+	if !errors.Is(err, errs.ErrInvalidPullRequestId) {
+		t.Fatalf(
+			"CreatePullRequest should fail with %v, got: %v",
+			errs.ErrInvalidPullRequestId,
+			err,
+		)
+	}
+
+	res, err := prService.GetPullRequestByNumber(ctx, "infra", 42)
+	if err != nil {
+		t.Fatalf("GetPullRequestByNumber should succeed, got: %v", err)
+	}
+	if res.PullRequest.PullRequestId != "infra#42" || *res.PullRequest.Number != 42 {
+		t.Fatalf("GetPullRequestByNumber should return infra#42, got: %v", res.PullRequest)
+	}
+	list, err := prService.ListPullRequests(ctx, entity.ListPullRequestsDTO{RepositoryName: "web"})
+	if err != nil {
+		t.Fatalf("ListPullRequests should succeed, got: %v", err)
+	}
+	if len(list.PullRequests) != 1 || list.PullRequests[0].PullRequestId != "web#42" {
+		t.Fatalf("ListPullRequests should return [web#42], got: %v", list.PullRequests)
+	}
+}
@@ -0,0 +1,239 @@
+package absence
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+var (
+	globalCtx = context.Background()
+	pool      *pgxpool.Pool
+	logger    *slog.Logger
+	repo      repository.BaseAbsenceRepository
+	userRepo  repository.BaseUserRepository
+)
+
+func TestMain(m *testing.M) {
+	err := godotenv.Load("..\\test.env")
+	if err != nil {
+		slog.Error("unable to load env, using default environment variables", "err", err)
+	}
+	logHandler := slog.NewTextHandler(
+		os.Stdout,
+		&slog.HandlerOptions{
+			Level:     slog.LevelDebug,
+			AddSource: true,
+		})
+
+	logger = slog.New(logHandler)
+	slog.SetDefault(logger)
+
+	connString := os.Getenv("TEST_DATABASE_URL")
+	pool, err = pgxpool.New(globalCtx, connString)
+	if err != nil {
+		slog.Error("Unable to connect to database", "err", err)
+		os.Exit(1)
+	}
+
+	if err := pool.Ping(globalCtx); err != nil {
+		slog.Error("Unable to ping database", "err", err)
+		os.Exit(1)
+	}
+
+	repo = postgres.NewPostgresAbsenceRepository(logger)
+	userRepo = postgres.NewPostgresUserRepository(logger)
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func setupTest(t *testing.T) (context.Context, func(), pgx.Tx) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		if err := tx.Rollback(globalCtx); err != nil {
+			t.Fatalf("error rolling back: %v", err)
+		}
+	})
+	return ctx, cancel, tx
+}
+
+func createTeamWithUsers(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+	userIds ...string,
+) error {
+	_, err := db.Exec(ctx, "INSERT INTO teams(name) VALUES ($1)", teamName)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO users (id, username, team_name, is_active)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, id := range userIds {
+		_, err := db.Exec(ctx, query, id, id, teamName, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAddAbsence(t *testing.T) {
+	t.Run("Invalid userId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		weekday := 1
+		err := repo.AddAbsence(ctx, tx, &entity.Absence{
+			UserId:  "u1",
+			Kind:    entity.AbsenceDayOff,
+			Weekday: &weekday,
+		})
+		if err == nil {
+			t.Fatal("AddAbsence expected to fail")
+		}
+	})
+	t.Run("Invalid period", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeamWithUsers(ctx, tx, "team", "u1")
+		if err != nil {
+			t.Fatalf("createTeamWithUsers expected to succeed, got: %v", err)
+		}
+
+		now := time.Now()
+		err = repo.AddAbsence(ctx, tx, &entity.Absence{
+			UserId:   "u1",
+			Kind:     entity.AbsenceVacation,
+			StartsAt: &now,
+			EndsAt:   &now,
+		})
+		if err == nil {
+			t.Fatal("AddAbsence expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeamWithUsers(ctx, tx, "team", "u1", "u2", "u3")
+		if err != nil {
+			t.Fatalf("createTeamWithUsers expected to succeed, got: %v", err)
+		}
+
+		from := time.Now().Add(-time.Hour)
+		to := time.Now().Add(time.Hour)
+		vacation := &entity.Absence{
+			UserId:   "u1",
+			Kind:     entity.AbsenceVacation,
+			StartsAt: &from,
+			EndsAt:   &to,
+		}
+		err = repo.AddAbsence(ctx, tx, vacation)
+		if err != nil {
+			t.Fatalf("AddAbsence expected to succeed, got: %v", err)
+		}
+		if vacation.Id == 0 {
+			t.Fatal("AddAbsence expected to set id")
+		}
+
+		weekday := int(time.Now().UTC().Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		err = repo.AddAbsence(ctx, tx, &entity.Absence{
+			UserId:  "u2",
+			Kind:    entity.AbsenceDayOff,
+			Weekday: &weekday,
+		})
+		if err != nil {
+			t.Fatalf("AddAbsence expected to succeed, got: %v", err)
+		}
+
+		users, err := userRepo.GetActiveByTeamName(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("GetActiveByTeamName expected to succeed, got: %v", err)
+		}
+		if len(users) != 1 || users[0].Id != "u3" {
+			t.Fatalf("GetActiveByTeamName expected only u3, got: %v", users)
+		}
+
+		absences, err := repo.GetAbsencesByUserId(ctx, tx, "u1")
+		if err != nil {
+			t.Fatalf("GetAbsencesByUserId expected to succeed, got: %v", err)
+		}
+		if len(absences) != 1 || absences[0].Kind != entity.AbsenceVacation {
+			t.Fatalf("GetAbsencesByUserId expected single vacation, got: %v", absences)
+		}
+	})
+}
+
+func TestRemoveAbsence(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.RemoveAbsence(ctx, tx, "u1", 1)
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("RemoveAbsence expected to fail with ErrBaseNotFound, got: %v", err)
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeamWithUsers(ctx, tx, "team", "u1")
+		if err != nil {
+			t.Fatalf("createTeamWithUsers expected to succeed, got: %v", err)
+		}
+		from := time.Now().Add(-time.Hour)
+		to := time.Now().Add(time.Hour)
+		absence := &entity.Absence{
+			UserId:   "u1",
+			Kind:     entity.AbsenceVacation,
+			StartsAt: &from,
+			EndsAt:   &to,
+		}
+		err = repo.AddAbsence(ctx, tx, absence)
+		if err != nil {
+			t.Fatalf("AddAbsence expected to succeed, got: %v", err)
+		}
+
+		err = repo.RemoveAbsence(ctx, tx, "u1", absence.Id)
+		if err != nil {
+			t.Fatalf("RemoveAbsence expected to succeed, got: %v", err)
+		}
+
+		users, err := userRepo.GetActiveByTeamName(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("GetActiveByTeamName expected to succeed, got: %v", err)
+		}
+		if len(users) != 1 {
+			t.Fatalf("GetActiveByTeamName expected to have len 1, got: %v", len(users))
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+var (
+	globalCtx = context.Background()
+	pool      *pgxpool.Pool
+	logger    *slog.Logger
+	repo      repository.BaseAssignmentRepository
+)
+
+func TestMain(m *testing.M) {
+	err := godotenv.Load("..\\test.env")
+	if err != nil {
+		slog.Error("unable to load env, using default environment variables", "err", err)
+	}
+	logHandler := slog.NewTextHandler(
+		os.Stdout,
+		&slog.HandlerOptions{
+			Level:     slog.LevelDebug,
+			AddSource: true,
+		})
+
+	logger = slog.New(logHandler)
+	slog.SetDefault(logger)
+
+	connString := os.Getenv("TEST_DATABASE_URL")
+	pool, err = pgxpool.New(globalCtx, connString)
+	if err != nil {
+		slog.Error("Unable to connect to database", "err", err)
+		os.Exit(1)
+	}
+
+	if err := pool.Ping(globalCtx); err != nil {
+		slog.Error("Unable to ping database", "err", err)
+		os.Exit(1)
+	}
+
+	repo = postgres.NewPostgresAssignmentRepository(logger)
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func setupTest(t *testing.T) (context.Context, func(), pgx.Tx) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		if err := tx.Rollback(globalCtx); err != nil {
+			t.Fatalf("error rolling back: %v", err)
+		}
+	})
+	return ctx, cancel, tx
+}
+
+func createPullRequest(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	teamName string,
+	userIds ...string,
+) error {
+	_, err := db.Exec(ctx, "INSERT INTO teams(name) VALUES ($1)", teamName)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO users (id, username, team_name, is_active)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, id := range userIds {
+		_, err := db.Exec(ctx, query, id, id, teamName, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Exec(
+		ctx,
+		"INSERT INTO pull_requests (id, name, author_id, status) VALUES ($1, $2, $3, $4)",
+		prId,
+		prId,
+		userIds[0],
+		entity.StatusOpen,
+	)
+	return err
+}
+
+func TestAddAssignment(t *testing.T) {
+	t.Run("Invalid prId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddAssignment(ctx, tx, &entity.Assignment{
+			PullRequestId: "pr1",
+			Kind:          entity.AssignmentCreate,
+			Seed:          1,
+		})
+		if err == nil {
+			t.Fatal("AddAssignment expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createPullRequest(ctx, tx, "pr1", "team", "u1", "u2", "u3")
+		if err != nil {
+			t.Fatalf("createPullRequest expected to succeed, got: %v", err)
+		}
+
+		assignment := &entity.Assignment{
+			PullRequestId: "pr1",
+			Kind:          entity.AssignmentCreate,
+			Seed:          math.MaxUint64,
+			Steps: []entity.AssignmentStep{
+				{
+					TeamName: "team",
+					Strategy: entity.StrategyRandom,
+					Count:    2,
+					Candidates: []entity.AssignmentCandidate{
+						{UserId: "u2", OpenReviews: 1},
+						{UserId: "u3", OpenReviews: 0},
+					},
+					Picked: []string{"u3", "u2"},
+				},
+			},
+			Reviewers: []string{"u3", "u2"},
+		}
+		err = repo.AddAssignment(ctx, tx, assignment)
+		if err != nil {
+			t.Fatalf("AddAssignment expected to succeed, got: %v", err)
+		}
+		if assignment.Id == 0 || assignment.CreatedAt == nil {
+			t.Fatalf("AddAssignment expected to set id and created_at, got: %v", assignment)
+		}
+
+		res, err := repo.GetAssignmentById(ctx, tx, assignment.Id)
+		if err != nil {
+			t.Fatalf("GetAssignmentById expected to succeed, got: %v", err)
+		}
+		if res.Seed != assignment.Seed {
+			t.Fatalf("GetAssignmentById expected seed %d, got: %d", assignment.Seed, res.Seed)
+		}
+		if !reflect.DeepEqual(res.Steps, assignment.Steps) {
+			t.Fatalf("GetAssignmentById expected steps %v, got: %v", assignment.Steps, res.Steps)
+		}
+
+		list, err := repo.GetAssignmentsByPrId(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetAssignmentsByPrId expected to succeed, got: %v", err)
+		}
+		if len(list) != 1 || !slices.Equal(list[0].Reviewers, assignment.Reviewers) {
+			t.Fatalf("GetAssignmentsByPrId expected single assignment, got: %v", list)
+		}
+	})
+}
+
+func TestGetAssignmentById(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		_, err := repo.GetAssignmentById(ctx, tx, 1)
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("GetAssignmentById expected to fail with ErrBaseNotFound, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,245 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+var (
+	globalCtx = context.Background()
+	pool      *pgxpool.Pool
+	logger    *slog.Logger
+	repo      repository.BaseCommentRepository
+)
+
+func TestMain(m *testing.M) {
+	err := godotenv.Load("..\\test.env")
+	if err != nil {
+		slog.Error("unable to load env, using default environment variables", "err", err)
+	}
+	logHandler := slog.NewTextHandler(
+		os.Stdout,
+		&slog.HandlerOptions{
+			Level:     slog.LevelDebug,
+			AddSource: true,
+		})
+
+	logger = slog.New(logHandler)
+	slog.SetDefault(logger)
+
+	connString := os.Getenv("TEST_DATABASE_URL")
+	pool, err = pgxpool.New(globalCtx, connString)
+	if err != nil {
+		slog.Error("Unable to connect to database", "err", err)
+		os.Exit(1)
+	}
+
+	if err := pool.Ping(globalCtx); err != nil {
+		slog.Error("Unable to ping database", "err", err)
+		os.Exit(1)
+	}
+
+	repo = postgres.NewPostgresCommentRepository(logger)
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func setupTest(t *testing.T) (context.Context, func(), pgx.Tx) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		if err := tx.Rollback(globalCtx); err != nil {
+			t.Fatalf("error rolling back: %v", err)
+		}
+	})
+	return ctx, cancel, tx
+}
+
+func createPullRequest(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	teamName string,
+	userIds ...string,
+) error {
+	_, err := db.Exec(ctx, "INSERT INTO teams(name) VALUES ($1)", teamName)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO users (id, username, team_name, is_active)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, id := range userIds {
+		_, err := db.Exec(ctx, query, id, id, teamName, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Exec(
+		ctx,
+		"INSERT INTO pull_requests (id, name, author_id, status) VALUES ($1, $2, $3, $4)",
+		prId,
+		prId,
+		userIds[0],
+		entity.StatusOpen,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range userIds[1:] {
+		_, err := db.Exec(
+			ctx,
+			"INSERT INTO pull_requests_users (pr_id, user_id) VALUES ($1, $2)",
+			prId,
+			id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAddComment(t *testing.T) {
+	t.Run("Invalid prId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddComment(ctx, tx, &entity.Comment{
+			PullRequestId: "pr1",
+			AuthorId:      "u1",
+			Body:          "comment",
+		})
+		if err == nil {
+			t.Fatal("AddComment expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createPullRequest(ctx, tx, "pr1", "team", "u1", "u2")
+		if err != nil {
+			t.Fatalf("createPullRequest expected to succeed, got: %v", err)
+		}
+
+		thread := &entity.Comment{PullRequestId: "pr1", AuthorId: "u2", Body: "why?"}
+		err = repo.AddComment(ctx, tx, thread)
+		if err != nil {
+			t.Fatalf("AddComment expected to succeed, got: %v", err)
+		}
+		if thread.Id == 0 || thread.CreatedAt.IsZero() {
+			t.Fatalf("AddComment expected to set id and created_at, got: %v", thread)
+		}
+		err = repo.AddComment(ctx, tx, &entity.Comment{
+			PullRequestId: "pr1",
+			ParentId:      &thread.Id,
+			AuthorId:      "u1",
+			Body:          "because",
+		})
+		if err != nil {
+			t.Fatalf("AddComment expected to succeed, got: %v", err)
+		}
+
+		comments, err := repo.GetCommentsByPrId(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetCommentsByPrId expected to succeed, got: %v", err)
+		}
+		if len(comments) != 2 || comments[0].Id != thread.Id ||
+			comments[1].ParentId == nil || *comments[1].ParentId != thread.Id {
+			t.Fatalf("GetCommentsByPrId expected thread with reply, got: %v", comments)
+		}
+	})
+}
+
+func TestGetCommentById(t *testing.T) {
+	ctx, cancel, tx := setupTest(t)
+	defer cancel()
+
+	_, err := repo.GetCommentById(ctx, tx, 1<<40)
+	if !errors.Is(err, errs.ErrBaseNotFound) {
+		t.Fatalf("GetCommentById expected to fail with ErrBaseNotFound, got: %v", err)
+	}
+}
+
+func TestResolveComment(t *testing.T) {
+	ctx, cancel, tx := setupTest(t)
+	defer cancel()
+
+	err := createPullRequest(ctx, tx, "pr1", "team", "u1", "u2")
+	if err != nil {
+		t.Fatalf("createPullRequest expected to succeed, got: %v", err)
+	}
+	threads := make([]*entity.Comment, 2)
+	for i := range threads {
+		threads[i] = &entity.Comment{PullRequestId: "pr1", AuthorId: "u2", Body: "fix"}
+		err = repo.AddComment(ctx, tx, threads[i])
+		if err != nil {
+			t.Fatalf("AddComment expected to succeed, got: %v", err)
+		}
+	}
+
+	count, err := repo.CountUnresolvedThreads(ctx, tx, "pr1")
+	if err != nil {
+		t.Fatalf("CountUnresolvedThreads expected to succeed, got: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("CountUnresolvedThreads expected 2, got: %d", count)
+	}
+
+	err = repo.ResolveComment(ctx, tx, threads[0].Id, "u1")
+	if err != nil {
+		t.Fatalf("ResolveComment expected to succeed, got: %v", err)
+	}
+	err = repo.ResolveComment(ctx, tx, threads[0].Id, "u2")
+	if err != nil {
+		t.Fatalf("ResolveComment expected to succeed, got: %v", err)
+	}
+
+	resolved, err := repo.GetCommentById(ctx, tx, threads[0].Id)
+	if err != nil {
+		t.Fatalf("GetCommentById expected to succeed, got: %v", err)
+	}
+	if resolved.ResolvedAt == nil || resolved.ResolvedBy == nil || *resolved.ResolvedBy != "u1" {
+		t.Fatalf("ResolveComment expected to keep first resolution, got: %v", resolved)
+	}
+	count, err = repo.CountUnresolvedThreads(ctx, tx, "pr1")
+	if err != nil {
+		t.Fatalf("CountUnresolvedThreads expected to succeed, got: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("CountUnresolvedThreads expected 1, got: %d", count)
+	}
+
+	counts, err := repo.CountUnresolvedThreadsByPrIds(ctx, tx, []string{"pr1", "pr2"})
+	if err != nil {
+		t.Fatalf("CountUnresolvedThreadsByPrIds expected to succeed, got: %v", err)
+	}
+	if len(counts) != 1 || counts["pr1"] != 1 {
+		t.Fatalf("CountUnresolvedThreadsByPrIds expected map[pr1:1], got: %v", counts)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
@@ -192,6 +193,110 @@ func TestGetPullRequestsByReviewerId(t *testing.T) {
 	})
 }
 
+func TestSetPullRequestLabels(t *testing.T) {
+	ctx, cancel, tx := setupTest(t)
+	defer cancel()
+
+	err := createTeam(ctx, tx, "team")
+	if err != nil {
+		t.Fatalf("createTeam expected to succeed, got: %v", err)
+	}
+	err = createUser(ctx, tx, "u1", "user1", "team")
+	if err != nil {
+		t.Fatalf("createUser expected to succeed, got: %v", err)
+	}
+	err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+		Id:              "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u1",
+		Status:          entity.StatusOpen,
+	})
+	if err != nil {
+		t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+	}
+	err = repo.AddPullRequestLabels(ctx, tx, "pr1", []string{"go", "sql"})
+	if err != nil {
+		t.Fatalf("AddPullRequestLabels expected to succeed, got: %v", err)
+	}
+
+	err = repo.SetPullRequestLabels(ctx, tx, "pr1", []string{"docs", "go"})
+	if err != nil {
+		t.Fatalf("SetPullRequestLabels expected to succeed, got: %v", err)
+	}
+	labels, err := repo.GetPullRequestLabels(ctx, tx, "pr1")
+	if err != nil {
+		t.Fatalf("GetPullRequestLabels expected to succeed, got: %v", err)
+	}
+	if !slices.Equal(labels, []string{"docs", "go"}) {
+		t.Fatalf("GetPullRequestLabels expected [docs go], got: %v", labels)
+	}
+
+	err = repo.SetPullRequestLabels(ctx, tx, "pr1", nil)
+	if err != nil {
+		t.Fatalf("SetPullRequestLabels expected to succeed, got: %v", err)
+	}
+	labels, err = repo.GetPullRequestLabels(ctx, tx, "pr1")
+	if err != nil {
+		t.Fatalf("GetPullRequestLabels expected to succeed, got: %v", err)
+	}
+	if len(labels) != 0 {
+		t.Fatalf("GetPullRequestLabels expected no labels, got: %v", labels)
+	}
+}
+
+func TestUpdatePullRequest(t *testing.T) {
+	t.Run("Invalid Id", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		name := "new name"
+		err := repo.UpdatePullRequest(ctx, tx, "pr1", &entity.PullRequestUpdate{
+			PullRequestName: &name,
+		})
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("UpdatePullRequest expected to fail with ErrBaseNotFound, got: %v", err)
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+		err = createUser(ctx, tx, "u1", "user1", "team")
+		if err != nil {
+			t.Fatalf("createUser expected to succeed, got: %v", err)
+		}
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+
+		description := "some description"
+		err = repo.UpdatePullRequest(ctx, tx, "pr1", &entity.PullRequestUpdate{
+			Description: &description,
+		})
+		if err != nil {
+			t.Fatalf("UpdatePullRequest expected to succeed, got: %v", err)
+		}
+
+		res, err := repo.GetPullRequestById(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestById expected to succeed, got: %v", err)
+		}
+		if res.PullRequestName != "pr1" || res.Description != description {
+			t.Fatalf("UpdatePullRequest expected to change only description, got: %v", res)
+		}
+	})
+}
+
 func TestGetPullRequestById(t *testing.T) {
 	t.Run("Invalid Id", func(t *testing.T) {
 		ctx, cancel, tx := setupTest(t)
@@ -237,6 +342,269 @@ func TestGetPullRequestById(t *testing.T) {
 	})
 }
 
+func TestGetPullRequestByIdForUpdate(t *testing.T) {
+	t.Run("Invalid Id", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		_, err := repo.GetPullRequestByIdForUpdate(ctx, tx, "invalid_id")
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("GetPullRequestByIdForUpdate expected to fail with ErrBaseNotFound, got: %v", err)
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+		err = createUser(ctx, tx, "u1", "user1", "team")
+		if err != nil {
+			t.Fatalf("createUserWithTeam expected to succeed, got: %v", err)
+		}
+
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+
+		res, err := repo.GetPullRequestByIdForUpdate(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByIdForUpdate expected to succeed, got: %v", err)
+		}
+		if res.Id != "pr1" || res.Status != entity.StatusOpen {
+			t.Fatalf("GetPullRequestByIdForUpdate expected to return open `pr1`, got: %v", res)
+		}
+	})
+}
+
+func TestGetPullRequestByNumber(t *testing.T) {
+	ctx, cancel, tx := setupTest(t)
+	defer cancel()
+
+	err := createTeam(ctx, tx, "team")
+	if err != nil {
+		t.Fatalf("createTeam expected to succeed, got: %v", err)
+	}
+	err = createUser(ctx, tx, "u1", "user1", "team")
+	if err != nil {
+		t.Fatalf("createUser expected to succeed, got: %v", err)
+	}
+	query := `
+		INSERT INTO repositories (name, team_name)
+		VALUES ('backend', 'team'), ('frontend', 'team')
+	`
+	_, err = tx.Exec(ctx, query)
+	if err != nil {
+		t.Fatalf("failed to insert repositories: %v", err)
+	}
+
+	number := 42
+	for _, name := range []string{"backend", "frontend"} {
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              name + "#42",
+			PullRequestName: "pr",
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+			RepositoryName:  &name,
+			Number:          &number,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+	}
+
+	res, err := repo.GetPullRequestByNumber(ctx, tx, "frontend", 42)
+	if err != nil {
+		t.Fatalf("GetPullRequestByNumber expected to succeed, got: %v", err)
+	}
+	if res.Id != "frontend#42" || *res.RepositoryName != "frontend" || *res.Number != 42 {
+		t.Fatalf("GetPullRequestByNumber expected frontend#42, got: %v", res)
+	}
+	_, err = repo.GetPullRequestByNumber(ctx, tx, "backend", 7)
+	if !errors.Is(err, errs.ErrBaseNotFound) {
+		t.Fatalf("GetPullRequestByNumber expected to fail with ErrBaseNotFound, got: %v", err)
+	}
+
+	backend := "backend"
+	err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+		Id:              "other",
+		PullRequestName: "pr",
+		AuthorId:        "u1",
+		Status:          entity.StatusOpen,
+		RepositoryName:  &backend,
+		Number:          &number,
+	})
+	if err == nil {
+		t.Fatal("AddPullRequest expected to fail with a taken number")
+	}
+}
+
+func TestListPullRequests(t *testing.T) {
+	ctx, cancel, tx := setupTest(t)
+	defer cancel()
+
+	for _, team := range []string{"team1", "team2"} {
+		err := createTeam(ctx, tx, team)
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+	}
+	users := map[string]string{"u1": "team1", "u2": "team1", "u3": "team2"}
+	for id, team := range users {
+		err := createUser(ctx, tx, id, "user"+id, team)
+		if err != nil {
+			t.Fatalf("createUser expected to succeed, got: %v", err)
+		}
+	}
+	prs := []entity.PullRequest{
+		{Id: "pr1", PullRequestName: "c", AuthorId: "u1", Status: entity.StatusOpen},
+		{Id: "pr2", PullRequestName: "a", AuthorId: "u1", Status: entity.StatusOpen},
+		{Id: "pr3", PullRequestName: "b", AuthorId: "u3", Status: entity.StatusOpen},
+	}
+	for i := range prs {
+		err := repo.AddPullRequest(ctx, tx, &prs[i])
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+	}
+	err := repo.AddReviewerToPullRequest(ctx, tx, "pr1", "u2")
+	if err != nil {
+		t.Fatalf("AddReviewerToPullRequest expected to succeed, got: %v", err)
+	}
+	err = repo.AddPullRequestLabels(ctx, tx, "pr3", []string{"go"})
+	if err != nil {
+		t.Fatalf("AddPullRequestLabels expected to succeed, got: %v", err)
+	}
+	err = repo.UpdatePullRequestStatus(ctx, tx, "pr2", entity.StatusMerged)
+	if err != nil {
+		t.Fatalf("UpdatePullRequestStatus expected to succeed, got: %v", err)
+	}
+
+	ids := func(prs []entity.PullRequest) []string {
+		res := make([]string, len(prs))
+		for i, pr := range prs {
+			res[i] = pr.Id
+		}
+		return res
+	}
+	str := func(s string) *string { return &s }
+	open := entity.StatusOpen
+	merged := time.Now().Add(-time.Hour)
+	cases := []struct {
+		name     string
+		filter   entity.PullRequestFilter
+		expected []string
+	}{
+		{"All by created_at", entity.PullRequestFilter{}, []string{"pr1", "pr2", "pr3"}},
+		{
+			"All by name desc",
+			entity.PullRequestFilter{SortBy: entity.SortName, Descending: true},
+			[]string{"pr1", "pr3", "pr2"},
+		},
+		{"By status", entity.PullRequestFilter{Status: &open}, []string{"pr1", "pr3"}},
+		{"By author", entity.PullRequestFilter{AuthorId: str("u1")}, []string{"pr1", "pr2"}},
+		{"By team", entity.PullRequestFilter{TeamName: str("team2")}, []string{"pr3"}},
+		{"By reviewer", entity.PullRequestFilter{ReviewerId: str("u2")}, []string{"pr1"}},
+		{"By label", entity.PullRequestFilter{Label: str("go")}, []string{"pr3"}},
+		{"By merged_at", entity.PullRequestFilter{MergedFrom: &merged}, []string{"pr2"}},
+		{"Limit", entity.PullRequestFilter{Limit: 2}, []string{"pr1", "pr2"}},
+		{
+			"After cursor",
+			entity.PullRequestFilter{After: &entity.PullRequestCursor{
+				CreatedAt: prs[0].CreatedAt,
+				Id:        "pr1",
+			}},
+			[]string{"pr2", "pr3"},
+		},
+	}
+	for _, c := range cases {
+		if c.filter.Limit == 0 {
+			c.filter.Limit = 10
+		}
+		res, err := repo.ListPullRequests(ctx, tx, &c.filter)
+		if err != nil {
+			t.Fatalf("%s: ListPullRequests expected to succeed, got: %v", c.name, err)
+		}
+		if !slices.Equal(ids(res), c.expected) {
+			t.Fatalf("%s: ListPullRequests expected %v, got: %v", c.name, c.expected, ids(res))
+		}
+	}
+}
+
+func TestGetByPrIds(t *testing.T) {
+	ctx, cancel, tx := setupTest(t)
+	defer cancel()
+
+	err := createTeam(ctx, tx, "team")
+	if err != nil {
+		t.Fatalf("createTeam expected to succeed, got: %v", err)
+	}
+	for _, id := range []string{"u1", "u2", "u3"} {
+		err = createUser(ctx, tx, id, id, "team")
+		if err != nil {
+			t.Fatalf("createUserWithTeam expected to succeed, got: %v", err)
+		}
+	}
+	for _, id := range []string{"pr1", "pr2", "pr3"} {
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              id,
+			PullRequestName: id,
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+	}
+	for _, id := range []string{"u2", "u3"} {
+		err = repo.AddReviewerToPullRequest(ctx, tx, "pr1", id)
+		if err != nil {
+			t.Fatalf("AddReviewerToPullRequest expected to succeed, got: %v", err)
+		}
+	}
+	err = repo.AddReviewerToPullRequest(ctx, tx, "pr2", "u2")
+	if err != nil {
+		t.Fatalf("AddReviewerToPullRequest expected to succeed, got: %v", err)
+	}
+	err = repo.AddPullRequestLabels(ctx, tx, "pr2", []string{"sql", "go"})
+	if err != nil {
+		t.Fatalf("AddPullRequestLabels expected to succeed, got: %v", err)
+	}
+
+	reviewers, err := repo.GetReviewersByPrIds(ctx, tx, []string{"pr1", "pr2"})
+	if err != nil {
+		t.Fatalf("GetReviewersByPrIds expected to succeed, got: %v", err)
+	}
+	expectedReviewers := []entity.PullRequestReviewer{
+		{PullRequestId: "pr1", UserId: "u2"},
+		{PullRequestId: "pr1", UserId: "u3"},
+		{PullRequestId: "pr2", UserId: "u2"},
+	}
+	if !slices.Equal(reviewers, expectedReviewers) {
+		t.Fatalf("GetReviewersByPrIds expected %v, got: %v", expectedReviewers, reviewers)
+	}
+
+	labels, err := repo.GetLabelsByPrIds(ctx, tx, []string{"pr1", "pr2", "pr3"})
+	if err != nil {
+		t.Fatalf("GetLabelsByPrIds expected to succeed, got: %v", err)
+	}
+	expectedLabels := []entity.PullRequestLabel{
+		{PullRequestId: "pr2", Label: "go"},
+		{PullRequestId: "pr2", Label: "sql"},
+	}
+	if !slices.Equal(labels, expectedLabels) {
+		t.Fatalf("GetLabelsByPrIds expected %v, got: %v", expectedLabels, labels)
+	}
+}
+
 func TestGetOpenPullRequestsByReviewerId(t *testing.T) {
 	t.Run("No prs", func(t *testing.T) {
 		ctx, cancel, tx := setupTest(t)
@@ -292,6 +660,78 @@ func TestGetOpenPullRequestsByReviewerId(t *testing.T) {
 	})
 }
 
+func TestGetOpenPullRequestsByTeamMembers(t *testing.T) {
+	t.Run("Invalid teamName", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		res, err := repo.GetOpenPullRequestsByTeamMembers(ctx, tx, "invalid_team")
+		if err != nil {
+			t.Fatalf("GetOpenPullRequestsByTeamMembers expected to succeed, got: %v", err)
+		}
+		if len(res) != 0 {
+			t.Fatalf("GetOpenPullRequestsByTeamMembers expected to have len 0, got: %v", len(res))
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+		for _, id := range []string{"u1", "u2", "u3"} {
+			err = createUser(ctx, tx, id, "user"+id, "team")
+			if err != nil {
+				t.Fatalf("createUserWithTeam expected to succeed, got: %v", err)
+			}
+		}
+
+		for _, id := range []string{"pr1", "pr2"} {
+			err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+				Id:              id,
+				PullRequestName: id,
+				AuthorId:        "u1",
+				Status:          entity.StatusOpen,
+			})
+			if err != nil {
+				t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+			}
+			err = repo.AddReviewerToPullRequest(ctx, tx, id, "u2")
+			if err != nil {
+				t.Fatalf("AddReviewerToPullRequest expected to succeed, got: %v", err)
+			}
+		}
+		err = repo.UpdatePullRequestStatus(ctx, tx, "pr2", entity.StatusMerged)
+		if err != nil {
+			t.Fatalf("UpdatePullRequestStatus expected to succeed, got: %v", err)
+		}
+
+		res, err := repo.GetOpenPullRequestsByTeamMembers(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("GetOpenPullRequestsByTeamMembers expected to succeed, got: %v", err)
+		}
+		if len(res) != 3 {
+			t.Fatalf("GetOpenPullRequestsByTeamMembers expected to have len 3, got: %v", len(res))
+		}
+		for _, stats := range res {
+			expected := 0
+			if stats.Id == "u2" {
+				expected = 1
+			}
+			if stats.OpenPullRequestsCount != expected {
+				t.Fatalf(
+					"OpenPullRequestsCount for %s expected %d, got: %v",
+					stats.Id,
+					expected,
+					stats.OpenPullRequestsCount,
+				)
+			}
+		}
+	})
+}
+
 func TestAddPullRequest(t *testing.T) {
 	t.Run("Already exists", func(t *testing.T) {
 		ctx, cancel, tx := setupTest(t)
@@ -347,6 +787,146 @@ func TestAddPullRequest(t *testing.T) {
 	})
 }
 
+func TestAddPullRequestPaths(t *testing.T) {
+	t.Run("Invalid prId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddPullRequestPaths(ctx, tx, "pr1", []string{"main.go"})
+		if err == nil {
+			t.Fatal("AddPullRequestPaths expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+		err = createUser(ctx, tx, "u1", "user1", "team")
+		if err != nil {
+			t.Fatalf("createUserWithTeam expected to succeed, got: %v", err)
+		}
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+
+		err = repo.AddPullRequestPaths(ctx, tx, "pr1", []string{"main.go", "api/openapi.yml", "main.go"})
+		if err != nil {
+			t.Fatalf("AddPullRequestPaths expected to succeed, got: %v", err)
+		}
+
+		paths, err := repo.GetPullRequestPaths(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestPaths expected to succeed, got: %v", err)
+		}
+		if len(paths) != 2 {
+			t.Fatalf("GetPullRequestPaths expected to have len 2, got: %v", len(paths))
+		}
+	})
+}
+
+func TestAddPullRequestLabels(t *testing.T) {
+	t.Run("Invalid prId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddPullRequestLabels(ctx, tx, "pr1", []string{"go"})
+		if err == nil {
+			t.Fatal("AddPullRequestLabels expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+		err = createUser(ctx, tx, "u1", "user1", "team")
+		if err != nil {
+			t.Fatalf("createUserWithTeam expected to succeed, got: %v", err)
+		}
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+
+		err = repo.AddPullRequestLabels(ctx, tx, "pr1", []string{"sql", "go", "sql"})
+		if err != nil {
+			t.Fatalf("AddPullRequestLabels expected to succeed, got: %v", err)
+		}
+
+		labels, err := repo.GetPullRequestLabels(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestLabels expected to succeed, got: %v", err)
+		}
+		if !slices.Equal(labels, []string{"go", "sql"}) {
+			t.Fatalf("GetPullRequestLabels expected [go sql], got: %v", labels)
+		}
+	})
+}
+
+func TestAddCompletedReviews(t *testing.T) {
+	ctx, cancel, tx := setupTest(t)
+	defer cancel()
+
+	err := createTeam(ctx, tx, "team")
+	if err != nil {
+		t.Fatalf("createTeam expected to succeed, got: %v", err)
+	}
+	for _, id := range []string{"u1", "u2", "u3"} {
+		err = createUser(ctx, tx, id, "user"+id, "team")
+		if err != nil {
+			t.Fatalf("createUser expected to succeed, got: %v", err)
+		}
+	}
+	err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+		Id:              "pr1",
+		PullRequestName: "pr1",
+		AuthorId:        "u1",
+		Status:          entity.StatusOpen,
+	})
+	if err != nil {
+		t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+	}
+	err = repo.AddReviewerToPullRequest(ctx, tx, "pr1", "u2")
+	if err != nil {
+		t.Fatalf("AddReviewerToPullRequest expected to succeed, got: %v", err)
+	}
+
+	err = repo.AddCompletedReviews(ctx, tx, "pr1")
+	if err != nil {
+		t.Fatalf("AddCompletedReviews expected to succeed, got: %v", err)
+	}
+
+	stats, err := repo.GetOpenPullRequestsByTeamMembers(ctx, tx, "team")
+	if err != nil {
+		t.Fatalf("GetOpenPullRequestsByTeamMembers expected to succeed, got: %v", err)
+	}
+	recent := make(map[string]int)
+	for _, s := range stats {
+		recent[s.Id] = s.RecentReviewsCount
+	}
+	if recent["u1"] != 0 || recent["u2"] != 1 || recent["u3"] != 0 {
+		t.Fatalf("RecentReviewsCount expected only u2 to have 1, got: %v", recent)
+	}
+}
+
 func TestUpdatePullRequestStatus(t *testing.T) {
 	t.Run("Invalid Id", func(t *testing.T) {
 		ctx, cancel, tx := setupTest(t)
@@ -388,6 +968,219 @@ func TestUpdatePullRequestStatus(t *testing.T) {
 		if err != nil {
 			t.Fatalf("UpdatePullRequestStatus expected to succeed, got: %v", err)
 		}
+
+		pr, err := repo.GetPullRequestById(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestById expected to succeed, got: %v", err)
+		}
+		if pr.MergedAt == nil || pr.CreatedAt.IsZero() {
+			t.Fatalf("GetPullRequestById expected created_at and merged_at, got: %v", pr)
+		}
+	})
+	t.Run("Closed at", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+		err = createUser(ctx, tx, "u1", "user1", "team")
+		if err != nil {
+			t.Fatalf("createUserWithTeam expected to succeed, got: %v", err)
+		}
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+
+		err = repo.UpdatePullRequestStatus(ctx, tx, "pr1", entity.StatusClosed)
+		if err != nil {
+			t.Fatalf("UpdatePullRequestStatus expected to succeed, got: %v", err)
+		}
+		pr, err := repo.GetPullRequestById(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestById expected to succeed, got: %v", err)
+		}
+		if pr.ClosedAt == nil {
+			t.Fatalf("GetPullRequestById expected closed_at, got: %v", pr)
+		}
+
+		err = repo.UpdatePullRequestStatus(ctx, tx, "pr1", entity.StatusOpen)
+		if err != nil {
+			t.Fatalf("UpdatePullRequestStatus expected to succeed, got: %v", err)
+		}
+		pr, err = repo.GetPullRequestById(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestById expected to succeed, got: %v", err)
+		}
+		if pr.ClosedAt != nil {
+			t.Fatalf("GetPullRequestById expected no closed_at after reopen, got: %v", pr.ClosedAt)
+		}
+	})
+}
+
+func TestGetPullRequestAncestors(t *testing.T) {
+	ctx, cancel, tx := setupTest(t)
+	defer cancel()
+
+	err := createTeam(ctx, tx, "team")
+	if err != nil {
+		t.Fatalf("createTeam expected to succeed, got: %v", err)
+	}
+	err = createUser(ctx, tx, "u1", "user1", "team")
+	if err != nil {
+		t.Fatalf("createUser expected to succeed, got: %v", err)
+	}
+	var parentId *string
+	for _, id := range []string{"pr1", "pr2", "pr3"} {
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              id,
+			PullRequestName: id,
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+			ParentId:        parentId,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+		parentId = &id
+	}
+
+	ancestors, err := repo.GetPullRequestAncestors(ctx, tx, "pr3")
+	if err != nil {
+		t.Fatalf("GetPullRequestAncestors expected to succeed, got: %v", err)
+	}
+	if len(ancestors) != 2 || ancestors[0].Id != "pr2" || ancestors[1].Id != "pr1" {
+		t.Fatalf("GetPullRequestAncestors expected [pr2 pr1], got: %v", ancestors)
+	}
+
+	parent := "pr3"
+	err = repo.UpdatePullRequest(ctx, tx, "pr1", &entity.PullRequestUpdate{ParentId: &parent})
+	if err != nil {
+		t.Fatalf("UpdatePullRequest expected to succeed, got: %v", err)
+	}
+	ancestors, err = repo.GetPullRequestAncestors(ctx, tx, "pr3")
+	if err != nil {
+		t.Fatalf("GetPullRequestAncestors expected to succeed, got: %v", err)
+	}
+	if len(ancestors) != 2 {
+		t.Fatalf("GetPullRequestAncestors expected to stop at the cycle, got: %v", ancestors)
+	}
+
+	unstack := ""
+	err = repo.UpdatePullRequest(ctx, tx, "pr2", &entity.PullRequestUpdate{ParentId: &unstack})
+	if err != nil {
+		t.Fatalf("UpdatePullRequest expected to succeed, got: %v", err)
+	}
+	ancestors, err = repo.GetPullRequestAncestors(ctx, tx, "pr2")
+	if err != nil {
+		t.Fatalf("GetPullRequestAncestors expected to succeed, got: %v", err)
+	}
+	if len(ancestors) != 0 {
+		t.Fatalf("GetPullRequestAncestors expected no ancestors, got: %v", ancestors)
+	}
+}
+
+func TestAddPullRequestEvent(t *testing.T) {
+	t.Run("Invalid prId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		status := entity.StatusOpen
+		err := repo.AddPullRequestEvent(ctx, tx, &entity.PullRequestEvent{
+			PullRequestId: "pr1",
+			Kind:          entity.EventStatusChanged,
+			ToStatus:      &status,
+		})
+		if err == nil {
+			t.Fatal("AddPullRequestEvent expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+		for _, id := range []string{"u1", "u2"} {
+			err = createUser(ctx, tx, id, "user"+id, "team")
+			if err != nil {
+				t.Fatalf("createUser expected to succeed, got: %v", err)
+			}
+		}
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+
+		actor := "u1"
+		reviewer := "u2"
+		status := entity.StatusOpen
+		events := []*entity.PullRequestEvent{
+			{PullRequestId: "pr1", Kind: entity.EventStatusChanged, ActorId: &actor, ToStatus: &status},
+			{PullRequestId: "pr1", Kind: entity.EventReviewerAdded, UserId: &reviewer},
+		}
+		for _, e := range events {
+			err = repo.AddPullRequestEvent(ctx, tx, e)
+			if err != nil {
+				t.Fatalf("AddPullRequestEvent expected to succeed, got: %v", err)
+			}
+			if e.Id == 0 || e.CreatedAt.IsZero() {
+				t.Fatalf("AddPullRequestEvent expected to set id and created_at, got: %v", e)
+			}
+		}
+
+		res, err := repo.GetPullRequestEvents(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestEvents expected to succeed, got: %v", err)
+		}
+		if len(res) != 2 || res[0].Kind != entity.EventStatusChanged ||
+			res[1].UserId == nil || *res[1].UserId != reviewer || res[1].ActorId != nil {
+			t.Fatalf("GetPullRequestEvents expected events in insert order, got: %v", res)
+		}
+	})
+	t.Run("Reviewer event without user", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("createTeam expected to succeed, got: %v", err)
+		}
+		err = createUser(ctx, tx, "u1", "user1", "team")
+		if err != nil {
+			t.Fatalf("createUser expected to succeed, got: %v", err)
+		}
+		err = repo.AddPullRequest(ctx, tx, &entity.PullRequest{
+			Id:              "pr1",
+			PullRequestName: "pr1",
+			AuthorId:        "u1",
+			Status:          entity.StatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("AddPullRequest expected to succeed, got: %v", err)
+		}
+
+		err = repo.AddPullRequestEvent(ctx, tx, &entity.PullRequestEvent{
+			PullRequestId: "pr1",
+			Kind:          entity.EventReviewerAdded,
+		})
+		if err == nil {
+			t.Fatal("AddPullRequestEvent expected to fail")
+		}
 	})
 }
 
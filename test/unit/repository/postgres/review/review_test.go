@@ -0,0 +1,224 @@
+package review
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+var (
+	globalCtx = context.Background()
+	pool      *pgxpool.Pool
+	logger    *slog.Logger
+	repo      repository.BaseReviewRepository
+)
+
+func TestMain(m *testing.M) {
+	err := godotenv.Load("..\\test.env")
+	if err != nil {
+		slog.Error("unable to load env, using default environment variables", "err", err)
+	}
+	logHandler := slog.NewTextHandler(
+		os.Stdout,
+		&slog.HandlerOptions{
+			Level:     slog.LevelDebug,
+			AddSource: true,
+		})
+
+	logger = slog.New(logHandler)
+	slog.SetDefault(logger)
+
+	connString := os.Getenv("TEST_DATABASE_URL")
+	pool, err = pgxpool.New(globalCtx, connString)
+	if err != nil {
+		slog.Error("Unable to connect to database", "err", err)
+		os.Exit(1)
+	}
+
+	if err := pool.Ping(globalCtx); err != nil {
+		slog.Error("Unable to ping database", "err", err)
+		os.Exit(1)
+	}
+
+	repo = postgres.NewPostgresReviewRepository(logger)
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func setupTest(t *testing.T) (context.Context, func(), pgx.Tx) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		if err := tx.Rollback(globalCtx); err != nil {
+			t.Fatalf("error rolling back: %v", err)
+		}
+	})
+	return ctx, cancel, tx
+}
+
+func createPullRequest(
+	ctx context.Context,
+	db repository.Querier,
+	prId string,
+	teamName string,
+	userIds ...string,
+) error {
+	_, err := db.Exec(ctx, "INSERT INTO teams(name) VALUES ($1)", teamName)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO users (id, username, team_name, is_active)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, id := range userIds {
+		_, err := db.Exec(ctx, query, id, id, teamName, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Exec(
+		ctx,
+		"INSERT INTO pull_requests (id, name, author_id, status) VALUES ($1, $2, $3, $4)",
+		prId,
+		prId,
+		userIds[0],
+		entity.StatusOpen,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range userIds[1:] {
+		_, err := db.Exec(
+			ctx,
+			"INSERT INTO pull_requests_users (pr_id, user_id) VALUES ($1, $2)",
+			prId,
+			id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSetReview(t *testing.T) {
+	t.Run("Invalid prId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.SetReview(ctx, tx, &entity.Review{
+			PullRequestId: "pr1",
+			UserId:        "u1",
+			Decision:      entity.ReviewApproved,
+		})
+		if err == nil {
+			t.Fatal("SetReview expected to fail")
+		}
+	})
+	t.Run("Replaces previous decision", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createPullRequest(ctx, tx, "pr1", "team", "u1", "u2", "u3")
+		if err != nil {
+			t.Fatalf("createPullRequest expected to succeed, got: %v", err)
+		}
+
+		review := &entity.Review{
+			PullRequestId: "pr1",
+			UserId:        "u2",
+			Decision:      entity.ReviewChangesRequested,
+			Comment:       "fix tests",
+		}
+		err = repo.SetReview(ctx, tx, review)
+		if err != nil {
+			t.Fatalf("SetReview expected to succeed, got: %v", err)
+		}
+		if review.SubmittedAt.IsZero() {
+			t.Fatalf("SetReview expected to set submitted_at, got: %v", review)
+		}
+
+		err = repo.SetReview(ctx, tx, &entity.Review{
+			PullRequestId: "pr1",
+			UserId:        "u2",
+			Decision:      entity.ReviewApproved,
+		})
+		if err != nil {
+			t.Fatalf("SetReview expected to succeed, got: %v", err)
+		}
+
+		reviews, err := repo.GetReviewsByPrId(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetReviewsByPrId expected to succeed, got: %v", err)
+		}
+		if len(reviews) != 1 {
+			t.Fatalf("GetReviewsByPrId expected single review, got: %v", reviews)
+		}
+		if reviews[0].Decision != entity.ReviewApproved || reviews[0].Comment != "" {
+			t.Fatalf("GetReviewsByPrId expected replaced decision, got: %v", reviews[0])
+		}
+	})
+}
+
+func TestGetReviewsByPrId(t *testing.T) {
+	t.Run("Skips removed reviewers", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createPullRequest(ctx, tx, "pr1", "team", "u1", "u2", "u3")
+		if err != nil {
+			t.Fatalf("createPullRequest expected to succeed, got: %v", err)
+		}
+		for _, id := range []string{"u2", "u3"} {
+			err = repo.SetReview(ctx, tx, &entity.Review{
+				PullRequestId: "pr1",
+				UserId:        id,
+				Decision:      entity.ReviewApproved,
+			})
+			if err != nil {
+				t.Fatalf("SetReview expected to succeed, got: %v", err)
+			}
+		}
+		_, err = tx.Exec(ctx, "DELETE FROM pull_requests_users WHERE user_id = $1", "u3")
+		if err != nil {
+			t.Fatalf("removing reviewer expected to succeed, got: %v", err)
+		}
+
+		reviews, err := repo.GetReviewsByPrId(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetReviewsByPrId expected to succeed, got: %v", err)
+		}
+		if len(reviews) != 1 || reviews[0].UserId != "u2" {
+			t.Fatalf("GetReviewsByPrId expected only review of u2, got: %v", reviews)
+		}
+
+		reviews, err = repo.GetReviewsByPrIds(ctx, tx, []string{"pr1", "pr2"})
+		if err != nil {
+			t.Fatalf("GetReviewsByPrIds expected to succeed, got: %v", err)
+		}
+		if len(reviews) != 1 || reviews[0].PullRequestId != "pr1" || reviews[0].UserId != "u2" {
+			t.Fatalf("GetReviewsByPrIds expected only review of u2, got: %v", reviews)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package rule
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+var (
+	globalCtx = context.Background()
+	pool      *pgxpool.Pool
+	logger    *slog.Logger
+	repo      repository.BaseReviewerRuleRepository
+)
+
+func TestMain(m *testing.M) {
+	err := godotenv.Load("..\\test.env")
+	if err != nil {
+		slog.Error("unable to load env, using default environment variables", "err", err)
+	}
+	logHandler := slog.NewTextHandler(
+		os.Stdout,
+		&slog.HandlerOptions{
+			Level:     slog.LevelDebug,
+			AddSource: true,
+		})
+
+	logger = slog.New(logHandler)
+	slog.SetDefault(logger)
+
+	connString := os.Getenv("TEST_DATABASE_URL")
+	pool, err = pgxpool.New(globalCtx, connString)
+	if err != nil {
+		slog.Error("Unable to connect to database", "err", err)
+		os.Exit(1)
+	}
+
+	if err := pool.Ping(globalCtx); err != nil {
+		slog.Error("Unable to ping database", "err", err)
+		os.Exit(1)
+	}
+
+	repo = postgres.NewPostgresReviewerRuleRepository(logger)
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func setupTest(t *testing.T) (context.Context, func(), pgx.Tx) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		if err := tx.Rollback(globalCtx); err != nil {
+			t.Fatalf("error rolling back: %v", err)
+		}
+	})
+	return ctx, cancel, tx
+}
+
+func createTeamWithUsers(
+	ctx context.Context,
+	db repository.Querier,
+	teamName string,
+	userIds ...string,
+) error {
+	_, err := db.Exec(ctx, "INSERT INTO teams(name) VALUES ($1)", teamName)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO users (id, username, team_name, is_active)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, id := range userIds {
+		_, err := db.Exec(ctx, query, id, id, teamName, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSetRule(t *testing.T) {
+	t.Run("Invalid reviewerId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeamWithUsers(ctx, tx, "team", "u1")
+		if err != nil {
+			t.Fatalf("createTeamWithUsers expected to succeed, got: %v", err)
+		}
+
+		err = repo.SetRule(ctx, tx, &entity.ReviewerRule{
+			AuthorId:   "u1",
+			ReviewerId: "u2",
+			Kind:       entity.RuleExclude,
+		})
+		if err == nil {
+			t.Fatal("SetRule expected to fail")
+		}
+	})
+	t.Run("Overwrite kind", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeamWithUsers(ctx, tx, "team", "u1", "u2")
+		if err != nil {
+			t.Fatalf("createTeamWithUsers expected to succeed, got: %v", err)
+		}
+
+		for _, kind := range []string{entity.RuleExclude, entity.RulePrefer} {
+			err = repo.SetRule(ctx, tx, &entity.ReviewerRule{
+				AuthorId:   "u1",
+				ReviewerId: "u2",
+				Kind:       kind,
+			})
+			if err != nil {
+				t.Fatalf("SetRule expected to succeed, got: %v", err)
+			}
+		}
+
+		rules, err := repo.GetRulesByAuthorId(ctx, tx, "u1")
+		if err != nil {
+			t.Fatalf("GetRulesByAuthorId expected to succeed, got: %v", err)
+		}
+		if len(rules) != 1 || rules[0].Kind != entity.RulePrefer {
+			t.Fatalf("GetRulesByAuthorId expected single PREFER rule, got: %v", rules)
+		}
+	})
+}
+
+func TestRemoveRule(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.RemoveRule(ctx, tx, "u1", "u2")
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("RemoveRule expected to fail with ErrBaseNotFound, got: %v", err)
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeamWithUsers(ctx, tx, "team", "u1", "u2")
+		if err != nil {
+			t.Fatalf("createTeamWithUsers expected to succeed, got: %v", err)
+		}
+		err = repo.SetRule(ctx, tx, &entity.ReviewerRule{
+			AuthorId:   "u1",
+			ReviewerId: "u2",
+			Kind:       entity.RuleExclude,
+		})
+		if err != nil {
+			t.Fatalf("SetRule expected to succeed, got: %v", err)
+		}
+
+		err = repo.RemoveRule(ctx, tx, "u1", "u2")
+		if err != nil {
+			t.Fatalf("RemoveRule expected to succeed, got: %v", err)
+		}
+
+		rules, err := repo.GetRulesByAuthorId(ctx, tx, "u1")
+		if err != nil {
+			t.Fatalf("GetRulesByAuthorId expected to succeed, got: %v", err)
+		}
+		if len(rules) != 0 {
+			t.Fatalf("GetRulesByAuthorId expected to have len 0, got: %v", len(rules))
+		}
+	})
+}
@@ -106,6 +106,121 @@ func TestAddTeam(t *testing.T) {
 	})
 }
 
+func TestUpdateTeam(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		strategy := entity.StrategyRandom
+		err := repo.UpdateTeam(ctx, tx, "test", &entity.TeamUpdate{ReviewerStrategy: &strategy})
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("UpdateTeam expected ErrBaseNotFound, got: %v", err)
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddTeam(ctx, tx, &entity.Team{
+			TeamName: "test",
+		})
+		if err != nil {
+			t.Fatalf("AddTeam expected to succeed, got: %v", err)
+		}
+
+		strategy := entity.StrategyRandom
+		err = repo.UpdateTeam(ctx, tx, "test", &entity.TeamUpdate{ReviewerStrategy: &strategy})
+		if err != nil {
+			t.Fatalf("UpdateTeam expected to succeed, got: %v", err)
+		}
+		team, err := repo.GetTeam(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetTeam expected to succeed, got: %v", err)
+		}
+		if team.ReviewerStrategy != strategy {
+			t.Fatalf("ReviewerStrategy expected %s, got: %s", strategy, team.ReviewerStrategy)
+		}
+	})
+	t.Run("Max open reviews", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddTeam(ctx, tx, &entity.Team{
+			TeamName: "test",
+		})
+		if err != nil {
+			t.Fatalf("AddTeam expected to succeed, got: %v", err)
+		}
+
+		limit := 3
+		err = repo.UpdateTeam(ctx, tx, "test", &entity.TeamUpdate{MaxOpenReviews: &limit})
+		if err != nil {
+			t.Fatalf("UpdateTeam expected to succeed, got: %v", err)
+		}
+		team, err := repo.GetTeam(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetTeam expected to succeed, got: %v", err)
+		}
+		if team.MaxOpenReviews == nil || *team.MaxOpenReviews != limit {
+			t.Fatalf("MaxOpenReviews expected %d, got: %v", limit, team.MaxOpenReviews)
+		}
+
+		err = repo.UpdateTeam(ctx, tx, "test", &entity.TeamUpdate{ClearMaxOpenReviews: true})
+		if err != nil {
+			t.Fatalf("UpdateTeam expected to succeed, got: %v", err)
+		}
+		team, err = repo.GetTeam(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetTeam expected to succeed, got: %v", err)
+		}
+		if team.MaxOpenReviews != nil {
+			t.Fatalf("MaxOpenReviews expected to be nil, got: %d", *team.MaxOpenReviews)
+		}
+	})
+}
+
+func TestSetRoundRobinCursor(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.SetRoundRobinCursor(ctx, tx, "test", "u1")
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("SetRoundRobinCursor expected ErrBaseNotFound, got: %v", err)
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddTeam(ctx, tx, &entity.Team{
+			TeamName: "test",
+		})
+		if err != nil {
+			t.Fatalf("AddTeam expected to succeed, got: %v", err)
+		}
+		team, err := repo.GetTeam(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetTeam expected to succeed, got: %v", err)
+		}
+		if team.RoundRobinCursor != "" {
+			t.Fatalf("RoundRobinCursor expected to be empty, got: %s", team.RoundRobinCursor)
+		}
+
+		err = repo.SetRoundRobinCursor(ctx, tx, "test", "u1")
+		if err != nil {
+			t.Fatalf("SetRoundRobinCursor expected to succeed, got: %v", err)
+		}
+		team, err = repo.GetTeam(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetTeam expected to succeed, got: %v", err)
+		}
+		if team.RoundRobinCursor != "u1" {
+			t.Fatalf("RoundRobinCursor expected u1, got: %s", team.RoundRobinCursor)
+		}
+	})
+}
+
 func TestGetTeam(t *testing.T) {
 	t.Run("Invalid name", func(t *testing.T) {
 		ctx, cancel, tx := setupTest(t)
@@ -137,4 +252,222 @@ func TestGetTeam(t *testing.T) {
 			t.Fatalf("GetTeam expected TeamName to be `test`, got: %v", team.TeamName)
 		}
 	})
+	t.Run("Reviewer strategy", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddTeam(ctx, tx, &entity.Team{
+			TeamName:         "test",
+			ReviewerStrategy: entity.StrategyRoundRobin,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam expected to succeed, got: %v", err)
+		}
+
+		team, err := repo.GetTeam(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetTeam expected to succeed, got: %v", err)
+		}
+		if team.ReviewerStrategy != entity.StrategyRoundRobin {
+			t.Fatalf(
+				"GetTeam expected ReviewerStrategy to be `%s`, got: %v",
+				entity.StrategyRoundRobin,
+				team.ReviewerStrategy,
+			)
+		}
+	})
+	t.Run("Reviewer count", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddTeam(ctx, tx, &entity.Team{
+			TeamName:     "test",
+			MinReviewers: 1,
+			MaxReviewers: 3,
+		})
+		if err != nil {
+			t.Fatalf("AddTeam expected to succeed, got: %v", err)
+		}
+
+		team, err := repo.GetTeam(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetTeam expected to succeed, got: %v", err)
+		}
+		if team.MinReviewers != 1 || team.MaxReviewers != 3 {
+			t.Fatalf(
+				"GetTeam expected reviewers range to be 1..3, got: %d..%d",
+				team.MinReviewers,
+				team.MaxReviewers,
+			)
+		}
+	})
+}
+
+func TestSetFallbackTeams(t *testing.T) {
+	t.Run("Invalid fallback team", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddTeam(ctx, tx, &entity.Team{TeamName: "test"})
+		if err != nil {
+			t.Fatalf("AddTeam expected to succeed, got: %v", err)
+		}
+
+		err = repo.SetFallbackTeams(ctx, tx, "test", []string{"invalid"})
+		if err == nil {
+			t.Fatal("SetFallbackTeams expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		for _, name := range []string{"test", "first", "second"} {
+			err := repo.AddTeam(ctx, tx, &entity.Team{TeamName: name})
+			if err != nil {
+				t.Fatalf("AddTeam expected to succeed, got: %v", err)
+			}
+		}
+
+		err := repo.SetFallbackTeams(ctx, tx, "test", []string{"second", "first"})
+		if err != nil {
+			t.Fatalf("SetFallbackTeams expected to succeed, got: %v", err)
+		}
+
+		teams, err := repo.GetFallbackTeams(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetFallbackTeams expected to succeed, got: %v", err)
+		}
+		if len(teams) != 2 || teams[0].TeamName != "second" || teams[1].TeamName != "first" {
+			t.Fatalf("GetFallbackTeams expected [second first], got: %v", teams)
+		}
+
+		err = repo.SetFallbackTeams(ctx, tx, "test", nil)
+		if err != nil {
+			t.Fatalf("SetFallbackTeams expected to succeed, got: %v", err)
+		}
+		teams, err = repo.GetFallbackTeams(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetFallbackTeams expected to succeed, got: %v", err)
+		}
+		if len(teams) != 0 {
+			t.Fatalf("GetFallbackTeams expected to have len 0, got: %v", len(teams))
+		}
+	})
+}
+
+func TestSetCodeOwnerRules(t *testing.T) {
+	t.Run("Invalid teamName", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.SetCodeOwnerRules(ctx, tx, "invalid", []entity.CodeOwnerRule{
+			{Pattern: "*.go", UserIds: []string{"u1"}},
+		})
+		if err == nil {
+			t.Fatal("SetCodeOwnerRules expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.AddTeam(ctx, tx, &entity.Team{TeamName: "test"})
+		if err != nil {
+			t.Fatalf("AddTeam expected to succeed, got: %v", err)
+		}
+
+		err = repo.SetCodeOwnerRules(ctx, tx, "test", []entity.CodeOwnerRule{
+			{Pattern: "*.go", UserIds: []string{"u1"}},
+			{Pattern: "/migrations/", TeamNames: []string{"dba"}},
+		})
+		if err != nil {
+			t.Fatalf("SetCodeOwnerRules expected to succeed, got: %v", err)
+		}
+
+		rules, err := repo.GetCodeOwnerRules(ctx, tx, "test")
+		if err != nil {
+			t.Fatalf("GetCodeOwnerRules expected to succeed, got: %v", err)
+		}
+		if len(rules) != 2 {
+			t.Fatalf("GetCodeOwnerRules expected to have len 2, got: %v", len(rules))
+		}
+		if rules[0].Pattern != "*.go" || len(rules[0].UserIds) != 1 || len(rules[0].TeamNames) != 0 {
+			t.Fatalf("GetCodeOwnerRules expected first rule `*.go` owned by u1, got: %v", rules[0])
+		}
+		if rules[1].Pattern != "/migrations/" || len(rules[1].TeamNames) != 1 {
+			t.Fatalf("GetCodeOwnerRules expected second rule `/migrations/` owned by dba, got: %v", rules[1])
+		}
+	})
+}
+
+func TestSetRepository(t *testing.T) {
+	t.Run("Invalid team", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.SetRepository(ctx, tx, &entity.Repository{
+			RepositoryName: "backend",
+			TeamName:       "invalid",
+		})
+		if err == nil {
+			t.Fatal("SetRepository expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		for _, name := range []string{"first", "second"} {
+			err := repo.AddTeam(ctx, tx, &entity.Team{TeamName: name})
+			if err != nil {
+				t.Fatalf("AddTeam expected to succeed, got: %v", err)
+			}
+		}
+
+		_, err := repo.GetRepository(ctx, tx, "backend")
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("GetRepository expected to fail with ErrBaseNotFound, got: %v", err)
+		}
+
+		for _, name := range []string{"frontend", "backend"} {
+			err = repo.SetRepository(ctx, tx, &entity.Repository{
+				RepositoryName: name,
+				TeamName:       "first",
+			})
+			if err != nil {
+				t.Fatalf("SetRepository expected to succeed, got: %v", err)
+			}
+		}
+		repos, err := repo.GetRepositoriesByTeamName(ctx, tx, "first")
+		if err != nil {
+			t.Fatalf("GetRepositoriesByTeamName expected to succeed, got: %v", err)
+		}
+		if len(repos) != 2 || repos[0].RepositoryName != "backend" {
+			t.Fatalf("GetRepositoriesByTeamName expected [backend frontend], got: %v", repos)
+		}
+
+		err = repo.SetRepository(ctx, tx, &entity.Repository{
+			RepositoryName:      "backend",
+			TeamName:            "second",
+			RouteReviewsToOwner: true,
+		})
+		if err != nil {
+			t.Fatalf("SetRepository expected to succeed, got: %v", err)
+		}
+		res, err := repo.GetRepository(ctx, tx, "backend")
+		if err != nil {
+			t.Fatalf("GetRepository expected to succeed, got: %v", err)
+		}
+		if res.TeamName != "second" || !res.RouteReviewsToOwner {
+			t.Fatalf("GetRepository expected the moved repository, got: %v", res)
+		}
+		repos, err = repo.GetRepositoriesByTeamName(ctx, tx, "first")
+		if err != nil {
+			t.Fatalf("GetRepositoriesByTeamName expected to succeed, got: %v", err)
+		}
+		if len(repos) != 1 || repos[0].RepositoryName != "frontend" {
+			t.Fatalf("GetRepositoriesByTeamName expected [frontend], got: %v", repos)
+		}
+	})
 }
@@ -500,3 +500,127 @@ func TestUpdateUser(t *testing.T) {
 		}
 	})
 }
+
+func TestSetSkills(t *testing.T) {
+	t.Run("Invalid userId", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := repo.SetSkills(ctx, tx, "u1", []string{"go"})
+		if err == nil {
+			t.Fatal("SetSkills expected to fail")
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("CreateTeam expected to succeed, got: %v", err)
+		}
+		err = repo.AddUsers(ctx, tx, []entity.User{
+			{Id: "u1", Username: "user1", TeamName: "team", IsActive: true},
+			{Id: "u2", Username: "user2", TeamName: "team", IsActive: true},
+		})
+		if err != nil {
+			t.Fatalf("AddUsers expected to succeed, got: %v", err)
+		}
+
+		err = repo.SetSkills(ctx, tx, "u1", []string{"go", "sql"})
+		if err != nil {
+			t.Fatalf("SetSkills expected to succeed, got: %v", err)
+		}
+		err = repo.SetSkills(ctx, tx, "u2", []string{"frontend"})
+		if err != nil {
+			t.Fatalf("SetSkills expected to succeed, got: %v", err)
+		}
+		err = repo.SetSkills(ctx, tx, "u2", []string{"sql"})
+		if err != nil {
+			t.Fatalf("SetSkills expected to succeed, got: %v", err)
+		}
+
+		skills, err := repo.GetSkillsByUserIds(ctx, tx, []string{"u1", "u2"})
+		if err != nil {
+			t.Fatalf("GetSkillsByUserIds expected to succeed, got: %v", err)
+		}
+		if len(skills) != 3 {
+			t.Fatalf("GetSkillsByUserIds expected to have len 3, got: %v", skills)
+		}
+
+		ids, err := repo.GetUserIdsBySkills(ctx, tx, []string{"go", "frontend"})
+		if err != nil {
+			t.Fatalf("GetUserIdsBySkills expected to succeed, got: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != "u1" {
+			t.Fatalf("GetUserIdsBySkills expected [u1], got: %v", ids)
+		}
+	})
+}
+
+func TestSetMaxOpenReviews(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		limit := 1
+		err := repo.SetMaxOpenReviews(ctx, tx, "u1", &limit)
+		if !errors.Is(err, errs.ErrBaseNotFound) {
+			t.Fatalf("SetMaxOpenReviews expected ErrBaseNotFound, got: %v", err)
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		ctx, cancel, tx := setupTest(t)
+		defer cancel()
+
+		err := createTeam(ctx, tx, "team")
+		if err != nil {
+			t.Fatalf("CreateTeam expected to succeed, got: %v", err)
+		}
+		err = repo.AddUsers(ctx, tx, []entity.User{
+			{Id: "u0", Username: "user0", TeamName: "team", IsActive: true},
+			{Id: "u1", Username: "user1", TeamName: "team", IsActive: true},
+		})
+		if err != nil {
+			t.Fatalf("AddUsers expected to succeed, got: %v", err)
+		}
+
+		limit := 3
+		err = repo.SetMaxOpenReviews(ctx, tx, "u1", &limit)
+		if err != nil {
+			t.Fatalf("SetMaxOpenReviews expected to succeed, got: %v", err)
+		}
+		user, err := repo.GetById(ctx, tx, "u1")
+		if err != nil {
+			t.Fatalf("GetById expected to succeed, got: %v", err)
+		}
+		if user.MaxOpenReviews == nil || *user.MaxOpenReviews != limit {
+			t.Fatalf("MaxOpenReviews expected %d, got: %v", limit, user.MaxOpenReviews)
+		}
+
+		err = createPrAndAssign(ctx, tx, "pr1", "u0", "u1")
+		if err != nil {
+			t.Fatalf("CreatePrAndAssign expected to succeed, got: %v", err)
+		}
+		reviewers, err := repo.GetReviewersByPrId(ctx, tx, "pr1")
+		if err != nil {
+			t.Fatalf("GetReviewersByPrId expected to succeed, got: %v", err)
+		}
+		if len(reviewers) != 1 || reviewers[0].MaxOpenReviews == nil ||
+			*reviewers[0].MaxOpenReviews != limit {
+			t.Fatalf("GetReviewersByPrId expected MaxOpenReviews %d, got: %v", limit, reviewers)
+		}
+
+		err = repo.SetMaxOpenReviews(ctx, tx, "u1", nil)
+		if err != nil {
+			t.Fatalf("SetMaxOpenReviews expected to succeed, got: %v", err)
+		}
+		user, err = repo.GetById(ctx, tx, "u1")
+		if err != nil {
+			t.Fatalf("GetById expected to succeed, got: %v", err)
+		}
+		if user.MaxOpenReviews != nil {
+			t.Fatalf("MaxOpenReviews expected nil, got: %v", *user.MaxOpenReviews)
+		}
+	})
+}
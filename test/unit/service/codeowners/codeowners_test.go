@@ -0,0 +1,45 @@
+package codeowners
+
+import (
+	"testing"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/service"
+)
+
+func TestMatchCodeOwnerPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/service/pr.go", true},
+		{"*.go", "README.md", false},
+		{"/cmd/", "cmd/main.go", true},
+		{"/cmd/", "internal/cmd/main.go", false},
+		{"docs/", "internal/docs/readme.md", true},
+		{"internal/*.go", "internal/main.go", true},
+		{"internal/*.go", "internal/service/pr.go", false},
+		{"internal/**/*.go", "internal/service/pr.go", true},
+		{"internal/**/*.go", "internal/pr.go", true},
+		{"**/migrations", "db/migrations/000001.sql", true},
+		{"migrations", "migrations/000001.sql", true},
+		{"api/openapi.yml", "api/openapi.yml", true},
+		{"api/openapi.yml", "web/api/openapi.yml", false},
+		{"pr?.go", "pr1.go", true},
+		{"pr?.go", "pr10.go", false},
+		{"", "main.go", false},
+	}
+
+	for _, c := range cases {
+		if res := service.MatchCodeOwnerPattern(c.pattern, c.path); res != c.match {
+			t.Fatalf(
+				"MatchCodeOwnerPattern(%q, %q) expected %v, got: %v",
+				c.pattern,
+				c.path,
+				c.match,
+				res,
+			)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package selector
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/entity"
+	errs "github.com/shirotame/avito-backend-assignment-autumn-2025/internal/errors"
+	"github.com/shirotame/avito-backend-assignment-autumn-2025/internal/service"
+)
+
+func newCandidates() []service.ReviewerCandidate {
+	return []service.ReviewerCandidate{
+		{UserId: "u1", OpenReviews: 3},
+		{UserId: "u2", OpenReviews: 0},
+		{UserId: "u3", OpenReviews: 5},
+		{UserId: "u4", OpenReviews: 1},
+	}
+}
+
+func TestNewReviewerSelector(t *testing.T) {
+	t.Run("Unknown strategy", func(t *testing.T) {
+		_, err := service.NewReviewerSelector("unknown")
+		if !errors.Is(err, errs.ErrUnknownReviewerStrategy) {
+			t.Fatalf("NewReviewerSelector expected ErrUnknownReviewerStrategy, got: %v", err)
+		}
+		if !errors.Is(err, errs.ErrBaseBadRequest) {
+			t.Fatalf("NewReviewerSelector expected ErrBaseBadRequest, got: %v", err)
+		}
+	})
+	t.Run("All ok", func(t *testing.T) {
+		for _, strategy := range []string{
+			"",
+			entity.StrategyRandom,
+			entity.StrategyRoundRobin,
+			entity.StrategyLeastLoaded,
+			entity.StrategyWeighted,
+		} {
+			_, err := service.NewReviewerSelector(strategy)
+			if err != nil {
+				t.Fatalf("NewReviewerSelector(%q) expected to succeed, got: %v", strategy, err)
+			}
+		}
+	})
+}
+
+func TestSelect(t *testing.T) {
+	strategies := []string{
+		entity.StrategyRandom,
+		entity.StrategyRoundRobin,
+		entity.StrategyLeastLoaded,
+		entity.StrategyWeighted,
+		entity.StrategyRecentHistory,
+	}
+
+	t.Run("No candidates", func(t *testing.T) {
+		for _, strategy := range strategies {
+			selector, _ := service.NewReviewerSelector(strategy)
+			res := selector.Select(service.NewAssignmentRand(1), "", nil, 2)
+			if len(res) != 0 {
+				t.Fatalf("%s: Select expected to have len 0, got: %v", strategy, len(res))
+			}
+		}
+	})
+	t.Run("Fewer candidates than requested", func(t *testing.T) {
+		for _, strategy := range strategies {
+			selector, _ := service.NewReviewerSelector(strategy)
+			res := selector.Select(service.NewAssignmentRand(1), "", newCandidates()[:1], 2)
+			if !slices.Equal(res, []string{"u1"}) {
+				t.Fatalf("%s: Select expected [u1], got: %v", strategy, res)
+			}
+		}
+	})
+	t.Run("Unique reviewers", func(t *testing.T) {
+		for _, strategy := range strategies {
+			selector, _ := service.NewReviewerSelector(strategy)
+			res := selector.Select(service.NewAssignmentRand(1), "", newCandidates(), 3)
+			if len(res) != 3 {
+				t.Fatalf("%s: Select expected to have len 3, got: %v", strategy, len(res))
+			}
+			slices.Sort(res)
+			if len(slices.Compact(res)) != 3 {
+				t.Fatalf("%s: Select expected unique reviewers, got: %v", strategy, res)
+			}
+		}
+	})
+}
+
+func TestRoundRobinSelect(t *testing.T) {
+	selector, _ := service.NewReviewerSelector(entity.StrategyRoundRobin)
+	rng := service.NewAssignmentRand(1)
+
+	res := selector.Select(rng, "", newCandidates(), 2)
+	if !slices.Equal(res, []string{"u1", "u2"}) {
+		t.Fatalf("Select expected [u1 u2], got: %v", res)
+	}
+	res = selector.Select(rng, "u2", newCandidates(), 2)
+	if !slices.Equal(res, []string{"u3", "u4"}) {
+		t.Fatalf("Select expected [u3 u4], got: %v", res)
+	}
+	res = selector.Select(rng, "u4", newCandidates(), 1)
+	if !slices.Equal(res, []string{"u1"}) {
+		t.Fatalf("Select expected [u1], got: %v", res)
+	}
+	res = selector.Select(rng, "u25", newCandidates(), 1)
+	if !slices.Equal(res, []string{"u3"}) {
+		t.Fatalf("Select after missing cursor expected [u3], got: %v", res)
+	}
+}
+
+func TestLeastLoadedSelect(t *testing.T) {
+	selector, _ := service.NewReviewerSelector(entity.StrategyLeastLoaded)
+
+	res := selector.Select(service.NewAssignmentRand(1), "", newCandidates(), 2)
+	if !slices.Equal(res, []string{"u2", "u4"}) {
+		t.Fatalf("Select expected [u2 u4], got: %v", res)
+	}
+}
+
+func TestRecentHistorySelect(t *testing.T) {
+	selector, _ := service.NewReviewerSelector(entity.StrategyRecentHistory)
+	candidates := []service.ReviewerCandidate{
+		{UserId: "u1", OpenReviews: 0, RecentReviews: 1000},
+		{UserId: "u2", OpenReviews: 5, RecentReviews: 0},
+	}
+
+	picks := make(map[string]int)
+	for seed := range uint64(100) {
+		res := selector.Select(service.NewAssignmentRand(seed), "", candidates, 1)
+		picks[res[0]]++
+	}
+	if picks["u2"] < 90 {
+		t.Fatalf("Select expected to prefer u2 with no recent reviews, got: %v", picks)
+	}
+}
+
+func TestSelectReproducible(t *testing.T) {
+	for _, strategy := range []string{
+		entity.StrategyRandom,
+		entity.StrategyLeastLoaded,
+		entity.StrategyWeighted,
+		entity.StrategyRecentHistory,
+	} {
+		selector, _ := service.NewReviewerSelector(strategy)
+		for seed := range uint64(20) {
+			first := selector.Select(service.NewAssignmentRand(seed), "", newCandidates(), 2)
+			second := selector.Select(service.NewAssignmentRand(seed), "", newCandidates(), 2)
+			if !slices.Equal(first, second) {
+				t.Fatalf("%s: Select with seed %d expected %v, got: %v", strategy, seed, first, second)
+			}
+		}
+	}
+}